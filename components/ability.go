@@ -0,0 +1,194 @@
+package components
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+)
+
+var AbilitiesDir = filepath.Join(DataRoot, "abilities")
+
+// AbilityStatus is a status applied to every target hit, with a Chance in
+// percent (always when 0).
+type AbilityStatus struct {
+	Name     string  `json:"name"`
+	Duration float64 `json:"duration"`
+	Chance   float64 `json:"chance"`
+}
+
+// Ability is a skill definition. Its Damage dice plus the Stat of the actor,
+// minus the Defense of the target, hurt or restore HP when Heal. With ToHit a
+// d20 is rolled first: a natural 1 misses and a natural 20 doubles the damage.
+// Cures lists the statuses removed from the targets.
+type Ability struct {
+	Name      string             `json:"name"`
+	Cost      map[string]float64 `json:"cost"`
+	Target    Targeting          `json:"target"`
+	Damage    string             `json:"damage"`
+	Stat      string             `json:"stat"`
+	Defense   string             `json:"defense"`
+	Heal      bool               `json:"heal"`
+	ToHit     bool               `json:"tohit"`
+	Element   string             `json:"element"`
+	Statuses  []AbilityStatus    `json:"statuses"`
+	Cures     []string           `json:"cures"`
+	Animation string             `json:"animation"`
+	damage    *DiceExpression
+}
+
+func (a *Ability) Validate() error {
+	if a.Name == "" {
+		return fmt.Errorf("Ability:Validate - ability has no name")
+	}
+
+	if err := a.Target.Validate(); err != nil {
+		return fmt.Errorf("Ability:Validate - ability %s: %s", a.Name, err)
+	}
+
+	for stat, cost := range a.Cost {
+		if !isPool(stat) {
+			return fmt.Errorf("Ability:Validate - ability %s costs %s which is not a pool", a.Name, stat)
+		}
+		if cost < 0 {
+			return fmt.Errorf("Ability:Validate - ability %s has a negative %s cost", a.Name, stat)
+		}
+	}
+
+	for _, stat := range []string{a.Stat, a.Defense} {
+		if stat != "" && !isStat(stat) {
+			return fmt.Errorf("Ability:Validate - ability %s uses unknown stat %s", a.Name, stat)
+		}
+	}
+
+	if a.Damage != "" {
+		dice, err := ParseDices(a.Damage)
+		if err != nil {
+			return fmt.Errorf("Ability:Validate - ability %s: %s", a.Name, err)
+		}
+		a.damage = dice
+	}
+
+	for _, s := range a.Statuses {
+		if !isStatus(s.Name) {
+			return fmt.Errorf("Ability:Validate - ability %s applies unknown status %s", a.Name, s.Name)
+		}
+		if s.Chance < 0 || s.Chance > 100 {
+			return fmt.Errorf("Ability:Validate - ability %s status %s chance must be within 0 and 100", a.Name, s.Name)
+		}
+	}
+
+	for _, name := range a.Cures {
+		if !isStatus(name) {
+			return fmt.Errorf("Ability:Validate - ability %s cures unknown status %s", a.Name, name)
+		}
+	}
+
+	if a.damage == nil && len(a.Statuses) == 0 && len(a.Cures) == 0 {
+		return fmt.Errorf("Ability:Validate - ability %s has no damage, status nor cure", a.Name)
+	}
+
+	return nil
+}
+
+// DamageDice returns the parsed Damage formula, nil when the ability does no
+// damage.
+func (a *Ability) DamageDice() *DiceExpression {
+	if a.damage == nil && a.Damage != "" {
+		a.damage, _ = ParseDices(a.Damage)
+	}
+
+	return a.damage
+}
+
+func (a *Ability) CanPay(ge *GameEntity) bool {
+	for stat, cost := range a.Cost {
+		if ge.Stats == nil || ge.Stats.Pool(stat) < cost {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (ge *GameEntity) Knows(ability string) bool {
+	for _, name := range ge.Abilities {
+		if name == ability {
+			return true
+		}
+	}
+
+	return false
+}
+
+type Abilities struct {
+	abilities map[string]*Ability
+}
+
+func NewAbilities() *Abilities {
+	return &Abilities{
+		abilities: make(map[string]*Ability),
+	}
+}
+
+func (ab *Abilities) Add(ability *Ability) error {
+	if err := ability.Validate(); err != nil {
+		return err
+	}
+	if ab.abilities[ability.Name] != nil {
+		return fmt.Errorf("Abilities:Add - ability %s already exists", ability.Name)
+	}
+
+	ab.abilities[ability.Name] = ability
+
+	return nil
+}
+
+func (ab *Abilities) Get(name string) *Ability {
+	return ab.abilities[name]
+}
+
+func (ab *Abilities) Names() []string {
+	names := make([]string, 0, len(ab.abilities))
+	for name := range ab.abilities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Load reads a JSON array of abilities.
+func (ab *Abilities) Load(data []byte) error {
+	var abilities []*Ability
+	if err := json.Unmarshal(data, &abilities); err != nil {
+		return fmt.Errorf("Abilities:Load - %s", err)
+	}
+
+	for _, a := range abilities {
+		if err := ab.Add(a); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (ab *Abilities) LoadDir(dir string) error {
+	return LoadDataDir(dir, func(file string, data []byte) error {
+		return ab.Load(data)
+	})
+}
+
+// Validate checks that every ability known by the entities exists.
+func (ab *Abilities) Validate(entities ...*GameEntity) error {
+	for _, ge := range entities {
+		for _, name := range ge.Abilities {
+			if ab.abilities[name] == nil {
+				return fmt.Errorf("Abilities:Validate - %s knows unknown ability %s", ge.Name, name)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,349 @@
+package components
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"path/filepath"
+	"sort"
+)
+
+const (
+	AiRandom      = "random"
+	AiLowestHp    = "lowest-hp"
+	AiHealWhenLow = "heal-when-low"
+	AiRules       = "rules"
+	AiBehaviour   = "behaviour"
+
+	AiPickRandom    = "random"
+	AiPickLowestHp  = "lowest-hp"
+	AiPickHighestHp = "highest-hp"
+
+	AiDefaultThreshold = 30
+)
+
+var AiDir = filepath.Join(DataRoot, "ai")
+
+// AiRule is an entry of a rules profile. Among the usable rules of the
+// highest Priority one is picked by Weight (1 by default). A rule is usable
+// when its ability can be paid, its actor HP is at most SelfHp percent and a
+// candidate has at most Hp percent HP and not the Unless status, zero values
+// skipping the checks. Target picks the focus among those candidates.
+type AiRule struct {
+	Ability  string  `json:"ability"`
+	Priority int     `json:"priority"`
+	Weight   float64 `json:"weight"`
+	Target   string  `json:"target"`
+	Hp       float64 `json:"hp"`
+	SelfHp   float64 `json:"selfhp"`
+	Unless   string  `json:"unless"`
+}
+
+func (r *AiRule) weight() float64 {
+	if r.Weight == 0 {
+		return 1
+	}
+
+	return r.Weight
+}
+
+// AiProfile is a named enemy behaviour. Strategy is the name of an
+// AiStrategy, Threshold the HP percent under which heal-when-low heals.
+type AiProfile struct {
+	Name      string   `json:"name"`
+	Strategy  string   `json:"strategy"`
+	Threshold float64  `json:"threshold"`
+	Rules     []AiRule `json:"rules"`
+}
+
+func (p *AiProfile) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("AiProfile:Validate - profile has no name")
+	}
+	if p.Strategy == "" {
+		return fmt.Errorf("AiProfile:Validate - profile %s has no strategy", p.Name)
+	}
+	if p.Threshold < 0 || p.Threshold > 100 {
+		return fmt.Errorf("AiProfile:Validate - profile %s threshold must be within 0 and 100", p.Name)
+	}
+	if p.Strategy == AiRules && len(p.Rules) == 0 {
+		return fmt.Errorf("AiProfile:Validate - profile %s has no rules", p.Name)
+	}
+
+	for _, r := range p.Rules {
+		if r.Ability == "" {
+			return fmt.Errorf("AiProfile:Validate - profile %s has a rule without ability", p.Name)
+		}
+		if r.Weight < 0 {
+			return fmt.Errorf("AiProfile:Validate - profile %s rule %s has a negative weight", p.Name, r.Ability)
+		}
+		switch r.Target {
+		case "", AiPickRandom, AiPickLowestHp, AiPickHighestHp:
+		default:
+			return fmt.Errorf("AiProfile:Validate - profile %s rule %s has unknown target %s", p.Name, r.Ability, r.Target)
+		}
+		if r.Unless != "" && !isStatus(r.Unless) {
+			return fmt.Errorf("AiProfile:Validate - profile %s rule %s checks unknown status %s", p.Name, r.Ability, r.Unless)
+		}
+	}
+
+	return nil
+}
+
+func (p *AiProfile) threshold() float64 {
+	if p.Threshold == 0 {
+		return AiDefaultThreshold
+	}
+
+	return p.Threshold
+}
+
+// AiContext is what a strategy knows when choosing, Abilities are the ones
+// the actor knows and can pay for.
+type AiContext struct {
+	Battle    *Battle
+	Actor     *GameEntity
+	Profile   *AiProfile
+	Abilities []*Ability
+	Rand      *rand.Rand
+}
+
+// AiDecision is the ability chosen by a strategy and the entity it focuses.
+type AiDecision struct {
+	Ability *Ability
+	Focus   *GameEntity
+}
+
+// AiStrategy chooses the decision of ctx.Actor, nil to fall back to a basic
+// attack.
+type AiStrategy func(ctx *AiContext) *AiDecision
+
+// HpRatio returns the HP percent left to ge, 100 without stats.
+func HpRatio(ge *GameEntity) float64 {
+	if ge.Stats == nil || ge.Stats.Get(StatHp) <= 0 {
+		return 100
+	}
+
+	return ge.Stats.Pool(StatHp) / ge.Stats.Get(StatHp) * 100
+}
+
+func (ctx *AiContext) Candidates(ability *Ability) Targets {
+	return ctx.Battle.Candidates(ctx.Actor, ability.Target.Mode)
+}
+
+// Offensive returns the abilities aimed at enemies.
+func (ctx *AiContext) Offensive() []*Ability {
+	offensive := make([]*Ability, 0)
+	for _, a := range ctx.Abilities {
+		switch a.Target.Mode {
+		case TargetAlly, TargetAllAllies, TargetSelf:
+			continue
+		}
+		if !a.Heal {
+			offensive = append(offensive, a)
+		}
+	}
+
+	return offensive
+}
+
+func (ctx *AiContext) Healing() []*Ability {
+	healing := make([]*Ability, 0)
+	for _, a := range ctx.Abilities {
+		if a.Heal {
+			healing = append(healing, a)
+		}
+	}
+
+	return healing
+}
+
+// Pick returns a target of candidates following an AiPick mode.
+func (ctx *AiContext) Pick(candidates Targets, mode string) *GameEntity {
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	switch mode {
+	case AiPickLowestHp, AiPickHighestHp:
+		picked := candidates[0]
+		for _, ge := range candidates[1:] {
+			if mode == AiPickLowestHp && HpRatio(ge) < HpRatio(picked) ||
+				mode == AiPickHighestHp && HpRatio(ge) > HpRatio(picked) {
+				picked = ge
+			}
+		}
+		return picked
+	}
+
+	return candidates[ctx.Rand.Intn(len(candidates))]
+}
+
+func (ctx *AiContext) random(abilities []*Ability) *Ability {
+	if len(abilities) == 0 {
+		return nil
+	}
+
+	return abilities[ctx.Rand.Intn(len(abilities))]
+}
+
+// AiRandomStrategy uses any ability on any candidate.
+func AiRandomStrategy(ctx *AiContext) *AiDecision {
+	ability := ctx.random(ctx.Abilities)
+	if ability == nil {
+		return nil
+	}
+
+	return &AiDecision{Ability: ability, Focus: ctx.Pick(ctx.Candidates(ability), AiPickRandom)}
+}
+
+// AiLowestHpStrategy focuses the weakest enemy with an offensive ability.
+func AiLowestHpStrategy(ctx *AiContext) *AiDecision {
+	ability := ctx.random(ctx.Offensive())
+	if ability == nil {
+		return AiRandomStrategy(ctx)
+	}
+
+	return &AiDecision{Ability: ability, Focus: ctx.Pick(ctx.Candidates(ability), AiPickLowestHp)}
+}
+
+// AiHealWhenLowStrategy heals the weakest ally once under the profile
+// threshold, and focuses the weakest enemy otherwise.
+func AiHealWhenLowStrategy(ctx *AiContext) *AiDecision {
+	for _, ability := range ctx.Healing() {
+		focus := ctx.Pick(ctx.Candidates(ability), AiPickLowestHp)
+		if focus != nil && HpRatio(focus) <= ctx.Profile.threshold() {
+			return &AiDecision{Ability: ability, Focus: focus}
+		}
+	}
+
+	return AiLowestHpStrategy(ctx)
+}
+
+// AiRulesStrategy follows the weighted priority rules of the profile, and
+// acts randomly when none is usable.
+func AiRulesStrategy(ctx *AiContext) *AiDecision {
+	rules := append([]AiRule(nil), ctx.Profile.Rules...)
+	sort.SliceStable(rules, func(i, j int) bool {
+		return rules[i].Priority > rules[j].Priority
+	})
+
+	for i := 0; i < len(rules); {
+		j := i
+		usable := make([]*AiDecision, 0)
+		weights := make([]float64, 0)
+		total := 0.0
+		for ; j < len(rules) && rules[j].Priority == rules[i].Priority; j++ {
+			if d := ctx.rule(&rules[j]); d != nil {
+				usable = append(usable, d)
+				weights = append(weights, rules[j].weight())
+				total += rules[j].weight()
+			}
+		}
+		i = j
+
+		if total <= 0 {
+			continue
+		}
+		r := ctx.Rand.Float64() * total
+		for k, d := range usable {
+			r -= weights[k]
+			if r < 0 {
+				return d
+			}
+		}
+		return usable[len(usable)-1]
+	}
+
+	return AiRandomStrategy(ctx)
+}
+
+func (ctx *AiContext) rule(r *AiRule) *AiDecision {
+	var ability *Ability
+	for _, a := range ctx.Abilities {
+		if a.Name == r.Ability {
+			ability = a
+		}
+	}
+	if ability == nil {
+		return nil
+	}
+	if r.SelfHp > 0 && HpRatio(ctx.Actor) > r.SelfHp {
+		return nil
+	}
+
+	candidates := make(Targets, 0)
+	for _, ge := range ctx.Candidates(ability) {
+		if r.Hp > 0 && HpRatio(ge) > r.Hp {
+			continue
+		}
+		if r.Unless != "" && ge.HasStatus(r.Unless) {
+			continue
+		}
+		candidates = append(candidates, ge)
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	return &AiDecision{Ability: ability, Focus: ctx.Pick(candidates, r.Target)}
+}
+
+type AiProfiles struct {
+	profiles map[string]*AiProfile
+}
+
+func NewAiProfiles() *AiProfiles {
+	return &AiProfiles{
+		profiles: make(map[string]*AiProfile),
+	}
+}
+
+func (ap *AiProfiles) Add(profile *AiProfile) error {
+	if err := profile.Validate(); err != nil {
+		return err
+	}
+	if ap.profiles[profile.Name] != nil {
+		return fmt.Errorf("AiProfiles:Add - profile %s already exists", profile.Name)
+	}
+
+	ap.profiles[profile.Name] = profile
+
+	return nil
+}
+
+func (ap *AiProfiles) Get(name string) *AiProfile {
+	return ap.profiles[name]
+}
+
+func (ap *AiProfiles) Names() []string {
+	names := make([]string, 0, len(ap.profiles))
+	for name := range ap.profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Load reads a JSON array of AI profiles.
+func (ap *AiProfiles) Load(data []byte) error {
+	var profiles []*AiProfile
+	if err := json.Unmarshal(data, &profiles); err != nil {
+		return fmt.Errorf("AiProfiles:Load - %s", err)
+	}
+
+	for _, p := range profiles {
+		if err := ap.Add(p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (ap *AiProfiles) LoadDir(dir string) error {
+	return LoadDataDir(dir, func(file string, data []byte) error {
+		return ap.Load(data)
+	})
+}
@@ -0,0 +1,57 @@
+package components
+
+import "math"
+
+const (
+	AtbWait   = "wait"
+	AtbActive = "active"
+
+	AtbMax = 100.0
+)
+
+// AtbBaseRate and AtbSpeedRate are the gauge points filled per second, and per
+// second for each point of SPD.
+var AtbBaseRate = 10.0
+var AtbSpeedRate = 2.0
+
+// Gauge fills at Rate points per second until it reaches Max.
+type Gauge struct {
+	Value float64
+	Max   float64
+	Rate  float64
+}
+
+func NewGauge(rate float64) *Gauge {
+	return &Gauge{
+		Max:  AtbMax,
+		Rate: rate,
+	}
+}
+
+// AtbRate returns the fill rate of ge from its speed.
+func AtbRate(ge *GameEntity) float64 {
+	return AtbBaseRate + AtbSpeedRate*math.Max(0, speed(ge))
+}
+
+// Fill advances the gauge by dt seconds and returns whether it is full.
+func (g *Gauge) Fill(dt float64) bool {
+	g.Value = math.Min(g.Max, g.Value+g.Rate*dt)
+
+	return g.Full()
+}
+
+func (g *Gauge) Full() bool {
+	return g.Value >= g.Max
+}
+
+func (g *Gauge) Ratio() float64 {
+	if g.Max <= 0 {
+		return 0
+	}
+
+	return g.Value / g.Max
+}
+
+func (g *Gauge) Reset() {
+	g.Value = 0
+}
@@ -0,0 +1,617 @@
+package components
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"path/filepath"
+	"sort"
+)
+
+type BtStatus int
+
+const (
+	BtSuccess BtStatus = iota
+	BtFailure
+	BtRunning
+)
+
+func (s BtStatus) String() string {
+	switch s {
+	case BtSuccess:
+		return "success"
+	case BtFailure:
+		return "failure"
+	}
+
+	return "running"
+}
+
+const (
+	BtNodeSequence  = "sequence"
+	BtNodeSelector  = "selector"
+	BtNodeParallel  = "parallel"
+	BtNodeDecorator = "decorator"
+	BtNodeCondition = "condition"
+	BtNodeAction    = "action"
+
+	BtInverter  = "inverter"
+	BtSucceeder = "succeeder"
+	BtRepeat    = "repeat"
+	BtCooldown  = "cooldown"
+
+	// BtKeyAi holds the AiContext of a combat turn, BtKeyDecision the
+	// AiDecision taken by the use action.
+	BtKeyAi       = "ai"
+	BtKeyDecision = "decision"
+)
+
+var BehavioursDir = filepath.Join(DataRoot, "behaviours")
+
+type Blackboard struct {
+	values map[string]any
+}
+
+func NewBlackboard() *Blackboard {
+	return &Blackboard{
+		values: make(map[string]any),
+	}
+}
+
+func (b *Blackboard) Get(key string) any {
+	return b.values[key]
+}
+
+func (b *Blackboard) Set(key string, value any) {
+	b.values[key] = value
+}
+
+func (b *Blackboard) Has(key string) bool {
+	_, ok := b.values[key]
+	return ok
+}
+
+func (b *Blackboard) Delete(key string) {
+	delete(b.values, key)
+}
+
+func (b *Blackboard) Keys() []string {
+	keys := make([]string, 0, len(b.values))
+	for key := range b.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// BtContext is given to every node of a tick. Dt is the elapsed time, in
+// seconds in the field and 1 per turn in combat.
+type BtContext struct {
+	GameEntity *GameEntity
+	Blackboard *Blackboard
+	Dt         float64
+	Rand       *rand.Rand
+}
+
+type BtNode interface {
+	Tick(ctx *BtContext) BtStatus
+	Reset()
+}
+
+// BtNodeDef is the JSON form of a node. Name is the condition or action of a
+// leaf, Children the nodes of a composite or the single child of a
+// decorator. Count is the number of repeats (0 forever), Duration the
+// cooldown and Success the children a parallel needs to succeed (0 all).
+type BtNodeDef struct {
+	Type      string         `json:"type"`
+	Name      string         `json:"name"`
+	Decorator string         `json:"decorator"`
+	Count     int            `json:"count"`
+	Duration  float64        `json:"duration"`
+	Success   int            `json:"success"`
+	Params    map[string]any `json:"params"`
+	Children  []*BtNodeDef   `json:"children"`
+}
+
+type btSequence struct {
+	children []BtNode
+	current  int
+}
+
+func (s *btSequence) Tick(ctx *BtContext) BtStatus {
+	for s.current < len(s.children) {
+		switch s.children[s.current].Tick(ctx) {
+		case BtRunning:
+			return BtRunning
+		case BtFailure:
+			s.Reset()
+			return BtFailure
+		}
+		s.current++
+	}
+
+	s.Reset()
+	return BtSuccess
+}
+
+func (s *btSequence) Reset() {
+	s.current = 0
+	for _, c := range s.children {
+		c.Reset()
+	}
+}
+
+type btSelector struct {
+	children []BtNode
+	current  int
+}
+
+func (s *btSelector) Tick(ctx *BtContext) BtStatus {
+	for s.current < len(s.children) {
+		switch s.children[s.current].Tick(ctx) {
+		case BtRunning:
+			return BtRunning
+		case BtSuccess:
+			s.Reset()
+			return BtSuccess
+		}
+		s.current++
+	}
+
+	s.Reset()
+	return BtFailure
+}
+
+func (s *btSelector) Reset() {
+	s.current = 0
+	for _, c := range s.children {
+		c.Reset()
+	}
+}
+
+// btParallel ticks every unfinished child, it succeeds once success of them
+// did and fails when too many failed for that.
+type btParallel struct {
+	children []BtNode
+	success  int
+	statuses []BtStatus
+}
+
+func (p *btParallel) Tick(ctx *BtContext) BtStatus {
+	succeeded, failed := 0, 0
+	for i, c := range p.children {
+		if p.statuses[i] == BtRunning {
+			p.statuses[i] = c.Tick(ctx)
+		}
+		switch p.statuses[i] {
+		case BtSuccess:
+			succeeded++
+		case BtFailure:
+			failed++
+		}
+	}
+
+	switch {
+	case succeeded >= p.success:
+		p.Reset()
+		return BtSuccess
+	case failed > len(p.children)-p.success:
+		p.Reset()
+		return BtFailure
+	}
+
+	return BtRunning
+}
+
+func (p *btParallel) Reset() {
+	for i, c := range p.children {
+		p.statuses[i] = BtRunning
+		c.Reset()
+	}
+}
+
+type btDecorator struct {
+	kind     string
+	child    BtNode
+	count    int
+	duration float64
+	repeats  int
+	cooldown float64
+}
+
+func (d *btDecorator) Tick(ctx *BtContext) BtStatus {
+	switch d.kind {
+	case BtInverter:
+		switch d.child.Tick(ctx) {
+		case BtSuccess:
+			return BtFailure
+		case BtFailure:
+			return BtSuccess
+		}
+		return BtRunning
+	case BtSucceeder:
+		if d.child.Tick(ctx) == BtRunning {
+			return BtRunning
+		}
+		return BtSuccess
+	case BtRepeat:
+		switch d.child.Tick(ctx) {
+		case BtFailure:
+			d.Reset()
+			return BtFailure
+		case BtSuccess:
+			d.repeats++
+			d.child.Reset()
+			if d.count > 0 && d.repeats >= d.count {
+				d.Reset()
+				return BtSuccess
+			}
+		}
+		return BtRunning
+	case BtCooldown:
+		if d.cooldown > 0 {
+			d.cooldown -= ctx.Dt
+			return BtFailure
+		}
+		status := d.child.Tick(ctx)
+		if status != BtRunning {
+			d.cooldown = d.duration
+		}
+		return status
+	}
+
+	return BtFailure
+}
+
+// Reset keeps the cooldown running, it only ends with time.
+func (d *btDecorator) Reset() {
+	d.repeats = 0
+	d.child.Reset()
+}
+
+// BtCondition checks the context, a leaf failing when it returns false.
+type BtCondition func(ctx *BtContext, leaf *BtLeaf) bool
+
+// BtAction acts for the entity of the context, BtRunning to go on next tick.
+type BtAction func(ctx *BtContext, leaf *BtLeaf) BtStatus
+
+// BtLeaf is a condition or action node. Running tells an action it returned
+// BtRunning on the previous tick, Elapsed sums the Dt since it started and
+// State keeps its own values until it completes.
+type BtLeaf struct {
+	Name      string
+	Params    map[string]any
+	Running   bool
+	Elapsed   float64
+	State     map[string]any
+	condition BtCondition
+	action    BtAction
+}
+
+func (l *BtLeaf) Tick(ctx *BtContext) BtStatus {
+	if l.condition != nil {
+		if l.condition(ctx, l) {
+			return BtSuccess
+		}
+		return BtFailure
+	}
+
+	l.Elapsed += ctx.Dt
+	status := l.action(ctx, l)
+	if status == BtRunning {
+		l.Running = true
+	} else {
+		l.Reset()
+	}
+
+	return status
+}
+
+func (l *BtLeaf) Reset() {
+	l.Running = false
+	l.Elapsed = 0
+	l.State = make(map[string]any)
+}
+
+func (l *BtLeaf) FloatParam(key string, def float64) float64 {
+	if v, ok := l.Params[key].(float64); ok {
+		return v
+	}
+
+	return def
+}
+
+func (l *BtLeaf) StringParam(key string) string {
+	s, _ := l.Params[key].(string)
+	return s
+}
+
+// BtLibrary holds the conditions and actions leaves refer to by name.
+type BtLibrary struct {
+	conditions map[string]BtCondition
+	actions    map[string]BtAction
+}
+
+// NewBtLibrary returns a library with the built-in leaves:
+//   - hp-below (percent), has-status (status), blackboard (key, value) and
+//     chance (percent) conditions
+//   - wait (duration), set (key, value), clear (key), log (message) and use
+//     (ability, target) actions
+func NewBtLibrary() *BtLibrary {
+	lib := &BtLibrary{
+		conditions: make(map[string]BtCondition),
+		actions:    make(map[string]BtAction),
+	}
+
+	lib.RegisterCondition("hp-below", btHpBelow)
+	lib.RegisterCondition("has-status", btHasStatus)
+	lib.RegisterCondition("blackboard", btBlackboard)
+	lib.RegisterCondition("chance", btChance)
+	lib.RegisterAction("wait", btWait)
+	lib.RegisterAction("set", btSet)
+	lib.RegisterAction("clear", btClear)
+	lib.RegisterAction("log", btLog)
+	lib.RegisterAction("use", btUse)
+
+	return lib
+}
+
+func (lib *BtLibrary) RegisterCondition(name string, condition BtCondition) {
+	lib.conditions[name] = condition
+}
+
+func (lib *BtLibrary) RegisterAction(name string, action BtAction) {
+	lib.actions[name] = action
+}
+
+// Build creates the runtime nodes of def, each call giving a tree with its
+// own state.
+func (lib *BtLibrary) Build(def *BtNodeDef) (BtNode, error) {
+	children := make([]BtNode, 0, len(def.Children))
+	for _, c := range def.Children {
+		child, err := lib.Build(c)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+	}
+
+	switch def.Type {
+	case BtNodeSequence, BtNodeSelector, BtNodeParallel:
+		if len(children) == 0 {
+			return nil, fmt.Errorf("BtLibrary:Build - %s %s has no children", def.Type, def.Name)
+		}
+	case BtNodeDecorator:
+		if len(children) != 1 {
+			return nil, fmt.Errorf("BtLibrary:Build - decorator %s needs a single child", def.Decorator)
+		}
+	}
+
+	switch def.Type {
+	case BtNodeSequence:
+		return &btSequence{children: children}, nil
+	case BtNodeSelector:
+		return &btSelector{children: children}, nil
+	case BtNodeParallel:
+		success := def.Success
+		if success <= 0 || success > len(children) {
+			success = len(children)
+		}
+		p := &btParallel{children: children, success: success, statuses: make([]BtStatus, len(children))}
+		p.Reset()
+		return p, nil
+	case BtNodeDecorator:
+		switch def.Decorator {
+		case BtInverter, BtSucceeder, BtRepeat, BtCooldown:
+		default:
+			return nil, fmt.Errorf("BtLibrary:Build - unknown decorator %s", def.Decorator)
+		}
+		return &btDecorator{kind: def.Decorator, child: children[0], count: def.Count, duration: def.Duration}, nil
+	case BtNodeCondition:
+		condition := lib.conditions[def.Name]
+		if condition == nil {
+			return nil, fmt.Errorf("BtLibrary:Build - unknown condition %s", def.Name)
+		}
+		return &BtLeaf{Name: def.Name, Params: def.Params, State: make(map[string]any), condition: condition}, nil
+	case BtNodeAction:
+		action := lib.actions[def.Name]
+		if action == nil {
+			return nil, fmt.Errorf("BtLibrary:Build - unknown action %s", def.Name)
+		}
+		return &BtLeaf{Name: def.Name, Params: def.Params, State: make(map[string]any), action: action}, nil
+	}
+
+	return nil, fmt.Errorf("BtLibrary:Build - unknown node type %s", def.Type)
+}
+
+func btHpBelow(ctx *BtContext, leaf *BtLeaf) bool {
+	return HpRatio(ctx.GameEntity) < leaf.FloatParam("percent", AiDefaultThreshold)
+}
+
+func btHasStatus(ctx *BtContext, leaf *BtLeaf) bool {
+	return ctx.GameEntity.HasStatus(leaf.StringParam("status"))
+}
+
+func btBlackboard(ctx *BtContext, leaf *BtLeaf) bool {
+	key := leaf.StringParam("key")
+	if value, ok := leaf.Params["value"]; ok {
+		return ctx.Blackboard.Get(key) == value
+	}
+
+	return ctx.Blackboard.Has(key)
+}
+
+func btChance(ctx *BtContext, leaf *BtLeaf) bool {
+	return ctx.Rand.Float64()*100 < leaf.FloatParam("percent", 50)
+}
+
+func btWait(ctx *BtContext, leaf *BtLeaf) BtStatus {
+	if leaf.Elapsed < leaf.FloatParam("duration", 1) {
+		return BtRunning
+	}
+
+	return BtSuccess
+}
+
+func btSet(ctx *BtContext, leaf *BtLeaf) BtStatus {
+	ctx.Blackboard.Set(leaf.StringParam("key"), leaf.Params["value"])
+	return BtSuccess
+}
+
+func btClear(ctx *BtContext, leaf *BtLeaf) BtStatus {
+	ctx.Blackboard.Delete(leaf.StringParam("key"))
+	return BtSuccess
+}
+
+func btLog(ctx *BtContext, leaf *BtLeaf) BtStatus {
+	fmt.Printf("BT:%s - %s\n", ctx.GameEntity.Name, leaf.StringParam("message"))
+	return BtSuccess
+}
+
+// btUse takes the combat decision of the turn: it stores an AiDecision on
+// the blackboard and keeps running, then succeeds on the next turn so a
+// sequence moves on to its next step.
+func btUse(ctx *BtContext, leaf *BtLeaf) BtStatus {
+	if leaf.Running {
+		return BtSuccess
+	}
+
+	ai, ok := ctx.Blackboard.Get(BtKeyAi).(*AiContext)
+	if !ok {
+		return BtFailure
+	}
+
+	name := leaf.StringParam("ability")
+	for _, a := range ai.Abilities {
+		if a.Name != name {
+			continue
+		}
+
+		focus := ai.Pick(ai.Candidates(a), leaf.StringParam("target"))
+		if focus == nil {
+			return BtFailure
+		}
+		ctx.Blackboard.Set(BtKeyDecision, &AiDecision{Ability: a, Focus: focus})
+		return BtRunning
+	}
+
+	return BtFailure
+}
+
+// BehaviourTree is a named tree definition.
+type BehaviourTree struct {
+	Name string     `json:"name"`
+	Root *BtNodeDef `json:"root"`
+}
+
+// BtInstance is the tree of one entity, with its own blackboard. Active
+// instances are ticked every frame, the others only when asked.
+type BtInstance struct {
+	Tree       string
+	GameEntity *GameEntity
+	Root       BtNode
+	Blackboard *Blackboard
+	Status     BtStatus
+	Active     bool
+}
+
+func (bi *BtInstance) Tick(dt float64, rnd *rand.Rand) BtStatus {
+	bi.Status = bi.Root.Tick(&BtContext{
+		GameEntity: bi.GameEntity,
+		Blackboard: bi.Blackboard,
+		Dt:         dt,
+		Rand:       rnd,
+	})
+
+	return bi.Status
+}
+
+type BehaviourTrees struct {
+	library *BtLibrary
+	trees   map[string]*BehaviourTree
+}
+
+func NewBehaviourTrees(library *BtLibrary) *BehaviourTrees {
+	return &BehaviourTrees{
+		library: library,
+		trees:   make(map[string]*BehaviourTree),
+	}
+}
+
+// Add checks that tree builds with the library.
+func (bt *BehaviourTrees) Add(tree *BehaviourTree) error {
+	if tree.Name == "" {
+		return fmt.Errorf("BehaviourTrees:Add - tree has no name")
+	}
+	if tree.Root == nil {
+		return fmt.Errorf("BehaviourTrees:Add - tree %s has no root", tree.Name)
+	}
+	if bt.trees[tree.Name] != nil {
+		return fmt.Errorf("BehaviourTrees:Add - tree %s already exists", tree.Name)
+	}
+	if _, err := bt.library.Build(tree.Root); err != nil {
+		return fmt.Errorf("BehaviourTrees:Add - tree %s: %s", tree.Name, err)
+	}
+
+	bt.trees[tree.Name] = tree
+
+	return nil
+}
+
+func (bt *BehaviourTrees) Get(name string) *BehaviourTree {
+	return bt.trees[name]
+}
+
+func (bt *BehaviourTrees) Names() []string {
+	names := make([]string, 0, len(bt.trees))
+	for name := range bt.trees {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// NewInstance builds the tree named name for ge.
+func (bt *BehaviourTrees) NewInstance(name string, ge *GameEntity) (*BtInstance, error) {
+	tree := bt.trees[name]
+	if tree == nil {
+		return nil, fmt.Errorf("BehaviourTrees:NewInstance - unknown tree %s", name)
+	}
+
+	root, err := bt.library.Build(tree.Root)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BtInstance{
+		Tree:       name,
+		GameEntity: ge,
+		Root:       root,
+		Blackboard: NewBlackboard(),
+		Status:     BtRunning,
+	}, nil
+}
+
+// Load reads a JSON array of trees.
+func (bt *BehaviourTrees) Load(data []byte) error {
+	var trees []*BehaviourTree
+	if err := json.Unmarshal(data, &trees); err != nil {
+		return fmt.Errorf("BehaviourTrees:Load - %s", err)
+	}
+
+	for _, t := range trees {
+		if err := bt.Add(t); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (bt *BehaviourTrees) LoadDir(dir string) error {
+	return LoadDataDir(dir, func(file string, data []byte) error {
+		return bt.Load(data)
+	})
+}
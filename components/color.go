@@ -14,6 +14,8 @@ var ColorOnHit = color.RGBA{R: 255}
 
 var ColorDamages = color.RGBA{R: 255, A: 255}
 var ColorHeal = color.RGBA{G: 255, A: 255}
+var ColorWeak = color.RGBA{R: 255, G: 140, A: 255}
+var ColorResist = color.RGBA{R: 160, G: 160, B: 160, A: 255}
 
 var ColorHighlight = color.RGBA{R: 255, G: 215, A: 255}
 var ColorActive = color.RGBA{B: 255, A: 255}
@@ -23,3 +25,7 @@ var ColorDisabled = color.RGBA{R: 0, G: 0, B: 0, A: 100}
 var ColorGreenLight = color.RGBA{R: 178, G: 192, B: 168, A: 255}
 var ColorGreen = color.RGBA{R: 118, G: 154, B: 103, A: 255}
 var ColorGreenDark = color.RGBA{R: 52, G: 93, B: 81, A: 255}
+
+var ColorGaugeBack = color.RGBA{R: 40, G: 40, B: 40, A: 200}
+var ColorGauge = color.RGBA{R: 118, G: 154, B: 255, A: 255}
+var ColorGaugeFull = color.RGBA{R: 255, G: 215, A: 255}
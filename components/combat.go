@@ -0,0 +1,179 @@
+package components
+
+import "sort"
+
+const (
+	ActionAttack = "Attack"
+	ActionUse    = "Use"
+	ActionCast   = "Cast"
+)
+
+var DefaultInitiative = "1d20"
+
+type Side struct {
+	Name      string
+	Entities  Targets
+	Player    bool
+	Formation map[*GameEntity]Slot
+}
+
+type Combatant struct {
+	GameEntity *GameEntity
+	Side       *Side
+	Initiative int
+	Roll       *RollResult
+	Gauge      *Gauge
+}
+
+type Action struct {
+	Name    string
+	Actor   *GameEntity
+	Targets Targets
+	Results []*ActionResult
+	Data    map[string]any
+}
+
+// ActionResult is the outcome of an action on one target, a negative Damage
+// heals. Affinity is the effect of the Element of the damage on the target.
+type ActionResult struct {
+	Target   *GameEntity
+	Damage   float64
+	Miss     bool
+	Critical bool
+	Element  string
+	Affinity string
+}
+
+// Battle is turn based with an empty Mode, or an active time battle with
+// AtbWait or AtbActive, Ready then holds the combatants waiting to act.
+type Battle struct {
+	Mode       string
+	Sides      []*Side
+	Queue      []*Combatant
+	Ready      []*Combatant
+	Initiative *DiceExpression
+	Round      int
+	Turn       int
+	Over       bool
+	Winner     *Side
+}
+
+func NewBattle(sides ...*Side) *Battle {
+	return &Battle{
+		Sides:      sides,
+		Queue:      make([]*Combatant, 0),
+		Ready:      make([]*Combatant, 0),
+		Initiative: MustParseDices(DefaultInitiative),
+	}
+}
+
+func IsAlive(ge *GameEntity) bool {
+	if ge.HasStatus(StatusDead) {
+		return false
+	}
+
+	return ge.Stats == nil || ge.Stats.Pool(StatHp) > 0
+}
+
+func (b *Battle) SideOf(ge *GameEntity) *Side {
+	for _, s := range b.Sides {
+		for _, e := range s.Entities {
+			if e == ge {
+				return s
+			}
+		}
+	}
+
+	return nil
+}
+
+func (b *Battle) Allies(ge *GameEntity) Targets {
+	allies := make(Targets, 0)
+	side := b.SideOf(ge)
+	if side == nil {
+		return allies
+	}
+
+	for _, e := range side.Entities {
+		if IsAlive(e) {
+			allies = append(allies, e)
+		}
+	}
+
+	return allies
+}
+
+func (b *Battle) Enemies(ge *GameEntity) Targets {
+	enemies := make(Targets, 0)
+	side := b.SideOf(ge)
+	for _, s := range b.Sides {
+		if s == side {
+			continue
+		}
+		for _, e := range s.Entities {
+			if IsAlive(e) {
+				enemies = append(enemies, e)
+			}
+		}
+	}
+
+	return enemies
+}
+
+// Standing returns the sides that still have a living entity.
+func (b *Battle) Standing() []*Side {
+	standing := make([]*Side, 0)
+	for _, s := range b.Sides {
+		for _, e := range s.Entities {
+			if IsAlive(e) {
+				standing = append(standing, s)
+				break
+			}
+		}
+	}
+
+	return standing
+}
+
+// SortQueue orders combatants by initiative, then speed, keeping the roll order
+// on ties.
+func (b *Battle) SortQueue() {
+	sort.SliceStable(b.Queue, func(i, j int) bool {
+		a, c := b.Queue[i], b.Queue[j]
+		if a.Initiative != c.Initiative {
+			return a.Initiative > c.Initiative
+		}
+
+		return speed(a.GameEntity) > speed(c.GameEntity)
+	})
+}
+
+func (b *Battle) Atb() bool {
+	return b.Mode == AtbWait || b.Mode == AtbActive
+}
+
+func (b *Battle) Combatant(ge *GameEntity) *Combatant {
+	for _, c := range b.Queue {
+		if c.GameEntity == ge {
+			return c
+		}
+	}
+
+	return nil
+}
+
+func (b *Battle) Current() *Combatant {
+	if b.Turn < 0 || b.Turn >= len(b.Queue) {
+		return nil
+	}
+
+	return b.Queue[b.Turn]
+}
+
+func speed(ge *GameEntity) float64 {
+	if ge.Stats == nil {
+		return 0
+	}
+
+	return ge.Stats.Get(StatSpd)
+}
@@ -0,0 +1,216 @@
+package components
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+const (
+	SymbolHit   = "hit"
+	SymbolCrit  = "crit"
+	SymbolBlank = "blank"
+)
+
+// DieFace is one side of a CustomDie. Value is summed across dice, Symbols are
+// counted, and Weight makes a face more likely (a zero weight counts as 1, a
+// negative one is invalid).
+type DieFace struct {
+	Label   string
+	Value   int
+	Symbols []string
+	Weight  float64
+}
+
+func (df DieFace) weight() float64 {
+	if df.Weight <= 0 {
+		return 1
+	}
+
+	return df.Weight
+}
+
+type CustomDie struct {
+	Name  string
+	Faces []DieFace
+}
+
+func NewCustomDie(name string, faces ...DieFace) *CustomDie {
+	cd := &CustomDie{
+		Name:  name,
+		Faces: faces,
+	}
+	if err := cd.Validate(); err != nil {
+		log.Fatalf("%s\n", err)
+	}
+
+	return cd
+}
+
+// Validate checks that the die can be rolled: it needs faces, and weights
+// can not be negative so the total weight is positive.
+func (cd *CustomDie) Validate() error {
+	if len(cd.Faces) == 0 {
+		return fmt.Errorf("CustomDie:Validate - die %s has no faces", cd.Name)
+	}
+
+	for _, f := range cd.Faces {
+		if f.Weight < 0 {
+			return fmt.Errorf("CustomDie:Validate - die %s face %s has a negative weight", cd.Name, f.Label)
+		}
+	}
+
+	return nil
+}
+
+func NewFudgeDie() *CustomDie {
+	return NewCustomDie("F",
+		DieFace{Label: "-", Value: -1},
+		DieFace{Label: "-", Value: -1},
+		DieFace{Label: " ", Value: 0},
+		DieFace{Label: " ", Value: 0},
+		DieFace{Label: "+", Value: 1},
+		DieFace{Label: "+", Value: 1},
+	)
+}
+
+func NewSymbolDie() *CustomDie {
+	return NewCustomDie("S",
+		DieFace{Label: "hit", Symbols: []string{SymbolHit}, Weight: 3},
+		DieFace{Label: "crit", Symbols: []string{SymbolHit, SymbolCrit}, Weight: 1},
+		DieFace{Label: "blank", Symbols: []string{SymbolBlank}, Weight: 2},
+	)
+}
+
+func (cd *CustomDie) RollWith(rnd *rand.Rand) DieFace {
+	total := 0.0
+	for _, f := range cd.Faces {
+		total += f.weight()
+	}
+
+	r := rnd.Float64() * total
+	for _, f := range cd.Faces {
+		r -= f.weight()
+		if r < 0 {
+			return f
+		}
+	}
+
+	return cd.Faces[len(cd.Faces)-1]
+}
+
+func (cd *CustomDie) Distribution() *DiceDistribution {
+	min, max := cd.Faces[0].Value, cd.Faces[0].Value
+	total := 0.0
+	for _, f := range cd.Faces {
+		if f.Value < min {
+			min = f.Value
+		}
+		if f.Value > max {
+			max = f.Value
+		}
+		total += f.weight()
+	}
+
+	dist := &DiceDistribution{
+		Min:   min,
+		Probs: make([]float64, max-min+1),
+	}
+	for _, f := range cd.Faces {
+		dist.Probs[f.Value-min] += f.weight() / total
+	}
+
+	return dist
+}
+
+type CustomDices struct {
+	X   int
+	Die *CustomDie
+}
+
+type CustomRollResult struct {
+	Dices   *CustomDices
+	Faces   []DieFace
+	Total   int
+	Symbols map[string]int
+}
+
+func NewCustomDices(die *CustomDie, x int) *CustomDices {
+	return &CustomDices{
+		X:   x,
+		Die: die,
+	}
+}
+
+func (cd *CustomDices) Roll() *CustomRollResult {
+	return cd.RollWith(GameRand)
+}
+
+func (cd *CustomDices) RollWith(rnd *rand.Rand) *CustomRollResult {
+	result := &CustomRollResult{
+		Dices:   cd,
+		Faces:   make([]DieFace, cd.X),
+		Symbols: make(map[string]int),
+	}
+
+	for i := range result.Faces {
+		face := cd.Die.RollWith(rnd)
+		result.Faces[i] = face
+		result.Total += face.Value
+		for _, s := range face.Symbols {
+			result.Symbols[s]++
+		}
+	}
+
+	return result
+}
+
+// Distribution returns the distribution of the summed face values.
+func (cd *CustomDices) Distribution() *DiceDistribution {
+	die := cd.Die.Distribution()
+	dist := NewConstantDistribution(0)
+	for i := 0; i < cd.X; i++ {
+		dist = dist.Add(die)
+	}
+
+	return dist
+}
+
+func (cd *CustomDices) String() string {
+	return fmt.Sprintf("%dd%s", cd.X, cd.Die.Name)
+}
+
+func (cr *CustomRollResult) Count(symbol string) int {
+	return cr.Symbols[symbol]
+}
+
+// Net returns the count of symbol minus the count of its opposite, e.g.
+// successes cancelled by failures.
+func (cr *CustomRollResult) Net(symbol string, opposite string) int {
+	return cr.Symbols[symbol] - cr.Symbols[opposite]
+}
+
+func (cr *CustomRollResult) String() string {
+	labels := make([]string, len(cr.Faces))
+	for i, f := range cr.Faces {
+		labels[i] = f.Label
+	}
+
+	symbols := make([]string, 0, len(cr.Symbols))
+	for s := range cr.Symbols {
+		symbols = append(symbols, s)
+	}
+	sort.Strings(symbols)
+	for i, s := range symbols {
+		symbols[i] = fmt.Sprintf("%d %s", cr.Symbols[s], s)
+	}
+
+	str := fmt.Sprintf("%s [%s] = %d", cr.Dices, strings.Join(labels, ","), cr.Total)
+	if len(symbols) > 0 {
+		str += fmt.Sprintf(" (%s)", strings.Join(symbols, ", "))
+	}
+
+	return str
+}
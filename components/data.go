@@ -0,0 +1,45 @@
+package components
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DataRoot holds data files, kept in sub directories so UiSystem.LoadAssets
+// does not hand them to the engo file loaders.
+const DataRoot = "assets"
+
+// LoadDataDir calls load with the content of every JSON file of dir, in name
+// order so that loading is deterministic.
+func LoadDataDir(dir string, load func(file string, data []byte) error) error {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("Data:LoadDir - %s", err)
+	}
+
+	names := make([]string, 0, len(files))
+	for _, f := range files {
+		if f.IsDir() || strings.ToLower(filepath.Ext(f.Name())) != ".json" {
+			continue
+		}
+		names = append(names, f.Name())
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		file := filepath.Join(dir, name)
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("Data:LoadDir - %s", err)
+		}
+
+		if err := load(file, data); err != nil {
+			return fmt.Errorf("Data:LoadDir - %s: %s", file, err)
+		}
+	}
+
+	return nil
+}
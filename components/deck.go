@@ -0,0 +1,153 @@
+package components
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+const (
+	PileDraw    = "draw"
+	PileHand    = "hand"
+	PileDiscard = "discard"
+	PileExhaust = "exhaust"
+)
+
+type Card struct {
+	Name string
+	Data map[string]any
+}
+
+func (c *Card) String() string {
+	return c.Name
+}
+
+// Pile keeps its top card at the end of the slice.
+type Pile []*Card
+
+func (p Pile) Index(card *Card) int {
+	for i, c := range p {
+		if c == card {
+			return i
+		}
+	}
+
+	return -1
+}
+
+type Deck struct {
+	Name  string
+	Piles map[string]Pile
+	Rand  *rand.Rand
+}
+
+func NewDeck(name string, cards []*Card, rnd *rand.Rand) *Deck {
+	if rnd == nil {
+		rnd = GameRand
+	}
+
+	deck := &Deck{
+		Name: name,
+		Piles: map[string]Pile{
+			PileDraw:    append(Pile(nil), cards...),
+			PileHand:    make(Pile, 0),
+			PileDiscard: make(Pile, 0),
+			PileExhaust: make(Pile, 0),
+		},
+		Rand: rnd,
+	}
+	deck.Shuffle(PileDraw)
+
+	return deck
+}
+
+func (d *Deck) Pile(name string) Pile {
+	return d.Piles[name]
+}
+
+func (d *Deck) Count(pile string) int {
+	return len(d.Piles[pile])
+}
+
+func (d *Deck) Shuffle(pile string) {
+	p := d.Piles[pile]
+	d.Rand.Shuffle(len(p), func(i, j int) {
+		p[i], p[j] = p[j], p[i]
+	})
+}
+
+// Peek returns up to n cards from the top of the pile, top card first.
+func (d *Deck) Peek(pile string, n int) []*Card {
+	p := d.Piles[pile]
+	cards := make([]*Card, 0, n)
+	for i := len(p) - 1; i >= 0 && len(cards) < n; i-- {
+		cards = append(cards, p[i])
+	}
+
+	return cards
+}
+
+// Reshuffle shuffles the discard pile back into the draw pile.
+func (d *Deck) Reshuffle() {
+	d.Piles[PileDraw] = append(d.Piles[PileDiscard], d.Piles[PileDraw]...)
+	d.Piles[PileDiscard] = make(Pile, 0)
+	d.Shuffle(PileDraw)
+}
+
+// Draw moves up to n cards from the draw pile to the hand, reshuffling the
+// discard pile when the draw pile runs out.
+func (d *Deck) Draw(n int) (drawn []*Card, reshuffled bool) {
+	drawn = make([]*Card, 0, n)
+	for len(drawn) < n {
+		if len(d.Piles[PileDraw]) == 0 {
+			if len(d.Piles[PileDiscard]) == 0 {
+				break
+			}
+			d.Reshuffle()
+			reshuffled = true
+		}
+
+		draw := d.Piles[PileDraw]
+		card := draw[len(draw)-1]
+		d.Piles[PileDraw] = draw[:len(draw)-1]
+		d.Piles[PileHand] = append(d.Piles[PileHand], card)
+		drawn = append(drawn, card)
+	}
+
+	return drawn, reshuffled
+}
+
+func (d *Deck) Move(card *Card, from string, to string) error {
+	p := d.Piles[from]
+	i := p.Index(card)
+	if i == -1 {
+		return fmt.Errorf("Deck:Move - card %s not in pile %s of deck %s", card, from, d.Name)
+	}
+
+	d.Piles[from] = append(p[:i], p[i+1:]...)
+	d.Piles[to] = append(d.Piles[to], card)
+
+	return nil
+}
+
+func (d *Deck) Discard(card *Card) error {
+	return d.Move(card, PileHand, PileDiscard)
+}
+
+func (d *Deck) DiscardHand() []*Card {
+	hand := d.Piles[PileHand]
+	d.Piles[PileDiscard] = append(d.Piles[PileDiscard], hand...)
+	d.Piles[PileHand] = make(Pile, 0)
+
+	return hand
+}
+
+// Find returns the pile holding card, or an empty string.
+func (d *Deck) Find(card *Card) string {
+	for name, p := range d.Piles {
+		if p.Index(card) != -1 {
+			return name
+		}
+	}
+
+	return ""
+}
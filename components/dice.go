@@ -1,22 +1,136 @@
 package components
 
+import (
+	"fmt"
+	"log"
+	"math/rand"
+	"sort"
+)
+
+const DiceExplodeLimit = 100
+
 type Dices struct {
-	X     int
-	Faces int
+	X       int
+	Faces   int
+	Keep    int
+	KeepLow bool
+	Explode bool
+	Reroll  int
 }
 
 func (d *Dices) Roll() float64 {
-	roll := 1
-	rnd := GameRand
+	return float64(d.roll(GameRand).Total)
+}
+
+func (d *Dices) RollDetailed() *RollResult {
+	return d.RollDetailedWith(GameRand)
+}
+
+func (d *Dices) RollWith(rnd *rand.Rand) float64 {
+	return float64(d.roll(rnd).Total)
+}
+
+func (d *Dices) RollDetailedWith(rnd *rand.Rand) *RollResult {
+	return newRollResult(d.String(), d.roll(rnd))
+}
+
+func (d *Dices) roll(rnd *rand.Rand) DiceGroupResult {
+	group := DiceGroupResult{
+		Dices: d,
+		Sign:  1,
+		Dice:  d.faces(rnd),
+	}
+	d.keep(group.Dice)
+
+	for _, die := range group.Dice {
+		if die.Kept {
+			group.Total += die.Value
+		}
+	}
+
+	return group
+}
+
+func (d *Dices) faces(rnd *rand.Rand) []DieResult {
+	dice := make([]DieResult, d.X)
+	for i := range dice {
+		face := rnd.Intn(d.Faces) + 1
+		rerolled := 0
+		if face <= d.Reroll {
+			rerolled = face
+			face = rnd.Intn(d.Faces) + 1
+		}
+		dice[i] = DieResult{
+			Value:    face,
+			Rolls:    []int{face},
+			Faces:    d.Faces,
+			Rerolled: rerolled,
+		}
+
+		if !d.Explode || d.Faces < 2 {
+			continue
+		}
 
-	for i := 0; i < d.X; i++ {
-		roll += rnd.Intn(d.Faces)
+		for n := 0; face == d.Faces && n < DiceExplodeLimit; n++ {
+			face = rnd.Intn(d.Faces) + 1
+			dice[i].Value += face
+			dice[i].Rolls = append(dice[i].Rolls, face)
+		}
 	}
 
-	return float64(roll)
+	return dice
+}
+
+func (d *Dices) keep(dice []DieResult) {
+	if d.Keep <= 0 || d.Keep >= len(dice) {
+		for i := range dice {
+			dice[i].Kept = true
+		}
+		return
+	}
+
+	order := make([]int, len(dice))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		if d.KeepLow {
+			return dice[order[a]].Value < dice[order[b]].Value
+		}
+		return dice[order[a]].Value > dice[order[b]].Value
+	})
+
+	for _, i := range order[:d.Keep] {
+		dice[i].Kept = true
+	}
+}
+
+func (d *Dices) String() string {
+	str := fmt.Sprintf("%dd%d", d.X, d.Faces)
+	if d.Keep > 0 && d.Keep < d.X {
+		if d.KeepLow {
+			str += fmt.Sprintf("kl%d", d.Keep)
+		} else {
+			str += fmt.Sprintf("kh%d", d.Keep)
+		}
+	}
+
+	if d.Reroll > 0 {
+		str += fmt.Sprintf("ro%d", d.Reroll)
+	}
+
+	if d.Explode {
+		str += "!"
+	}
+
+	return str
 }
 
 func NewDices(faces int, x int) *Dices {
+	if faces < 1 || x < 1 {
+		log.Fatalf("Dices:New - can not roll %d dice of %d faces\n", x, faces)
+	}
+
 	return &Dices{
 		X:     x,
 		Faces: faces,
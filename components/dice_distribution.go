@@ -0,0 +1,366 @@
+package components
+
+import (
+	"log"
+	"math"
+)
+
+const (
+	// DiceEpsilon bounds the probability mass ignored when computing the
+	// distribution of exploding dice, whose tail is otherwise unbounded.
+	DiceEpsilon = 1e-15
+	// DiceExactLimit bounds the work of an exact keep distribution, larger
+	// pools such as 1000d6k999 are sampled DiceSamples times instead.
+	DiceExactLimit = 5e8
+	DiceSamples    = 2000
+)
+
+type DiceDistribution struct {
+	Min   int
+	Probs []float64
+}
+
+func NewConstantDistribution(value int) *DiceDistribution {
+	return &DiceDistribution{
+		Min:   value,
+		Probs: []float64{1},
+	}
+}
+
+func NewUniformDistribution(min int, max int) *DiceDistribution {
+	if max < min {
+		log.Fatalf("DiceDistribution:NewUniform - max %d is lower than min %d\n", max, min)
+	}
+
+	dist := &DiceDistribution{
+		Min:   min,
+		Probs: make([]float64, max-min+1),
+	}
+	for i := range dist.Probs {
+		dist.Probs[i] = 1 / float64(len(dist.Probs))
+	}
+
+	return dist
+}
+
+// Distribution returns the probabilities of the totals, a constant 0 for
+// dices without faces or count.
+func (d *Dices) Distribution() *DiceDistribution {
+	if d.Faces < 1 || d.X < 1 {
+		return NewConstantDistribution(0)
+	}
+
+	die := d.dieDistribution()
+	if d.Keep > 0 && d.Keep < d.X {
+		if keepCost(die, d.X, d.Keep) > DiceExactLimit {
+			return d.sampleDistribution(DiceSamples)
+		}
+		return keepDistribution(die, d.X, d.Keep, d.KeepLow)
+	}
+
+	dist := NewConstantDistribution(0)
+	for i := 0; i < d.X; i++ {
+		dist = dist.Add(die)
+	}
+
+	return dist
+}
+
+// sampleDistribution estimates the distribution from rolls of a fixed stream,
+// so the estimate is the same every time and no game stream moves.
+func (d *Dices) sampleDistribution(samples int) *DiceDistribution {
+	rnd := NewRandStream("sample", 1).Rand
+	counts := make(map[int]int)
+	min, max := 0, 0
+	for i := 0; i < samples; i++ {
+		total := d.roll(rnd).Total
+		if i == 0 || total < min {
+			min = total
+		}
+		if i == 0 || total > max {
+			max = total
+		}
+		counts[total]++
+	}
+
+	dist := &DiceDistribution{
+		Min:   min,
+		Probs: make([]float64, max-min+1),
+	}
+	for total, count := range counts {
+		dist.Probs[total-min] = float64(count) / float64(samples)
+	}
+
+	return dist
+}
+
+func (d *Dices) dieDistribution() *DiceDistribution {
+	first := NewUniformDistribution(1, d.Faces)
+	if d.Reroll > 0 {
+		// A face up to Reroll is replaced by a second roll, which is final
+		f := float64(d.Faces)
+		for i := range first.Probs {
+			first.Probs[i] = float64(d.Reroll) / (f * f)
+			if i+1 > d.Reroll {
+				first.Probs[i] += 1 / f
+			}
+		}
+	}
+
+	if !d.Explode || d.Faces < 2 {
+		return first
+	}
+
+	// The highest face chains into an exploding die with one explosion less
+	chain := explodeDistribution(d.Faces, DiceExplodeLimit-1).Shift(d.Faces)
+	dist := &DiceDistribution{
+		Min:   1,
+		Probs: make([]float64, chain.Max()),
+	}
+	for v := 1; v < d.Faces; v++ {
+		dist.Probs[v-1] = first.P(v)
+	}
+	for i, p := range chain.Probs {
+		dist.Probs[chain.Min+i-1] += first.P(d.Faces) * p
+	}
+
+	return dist
+}
+
+func explodeDistribution(faces int, limit int) *DiceDistribution {
+	// After k explosions a face below the maximum stops the chain, the last
+	// allowed reroll keeps any face. A total of exactly k*faces never happens.
+	f := float64(faces)
+	dist := &DiceDistribution{Min: 1}
+	for k := 0; k <= limit; k++ {
+		p := math.Pow(1/f, float64(k+1))
+		if k > 0 {
+			dist.Probs = append(dist.Probs, 0)
+		}
+		for r := 1; r < faces; r++ {
+			dist.Probs = append(dist.Probs, p)
+		}
+		if k == limit || p < DiceEpsilon {
+			dist.Probs = append(dist.Probs, p)
+			break
+		}
+	}
+
+	return dist
+}
+
+func (de *DiceExpression) Distribution() *DiceDistribution {
+	dist := NewConstantDistribution(0)
+	for _, t := range de.Terms {
+		term := NewConstantDistribution(t.Flat)
+		if t.Dices != nil {
+			term = t.Dices.Distribution()
+		}
+		if t.Sign < 0 {
+			term = term.Negate()
+		}
+		dist = dist.Add(term)
+	}
+
+	return dist
+}
+
+func (dist *DiceDistribution) Max() int {
+	return dist.Min + len(dist.Probs) - 1
+}
+
+func (dist *DiceDistribution) P(value int) float64 {
+	i := value - dist.Min
+	if i < 0 || i >= len(dist.Probs) {
+		return 0
+	}
+
+	return dist.Probs[i]
+}
+
+func (dist *DiceDistribution) AtLeast(value int) float64 {
+	p := 0.0
+	for v := dist.Max(); v >= value && v >= dist.Min; v-- {
+		p += dist.P(v)
+	}
+
+	return math.Min(p, 1)
+}
+
+func (dist *DiceDistribution) AtMost(value int) float64 {
+	p := 0.0
+	for v := dist.Min; v <= value && v <= dist.Max(); v++ {
+		p += dist.P(v)
+	}
+
+	return math.Min(p, 1)
+}
+
+func (dist *DiceDistribution) Mean() float64 {
+	mean := 0.0
+	for i, p := range dist.Probs {
+		mean += float64(dist.Min+i) * p
+	}
+
+	return mean
+}
+
+func (dist *DiceDistribution) Variance() float64 {
+	mean := dist.Mean()
+	variance := 0.0
+	for i, p := range dist.Probs {
+		delta := float64(dist.Min+i) - mean
+		variance += delta * delta * p
+	}
+
+	return variance
+}
+
+func (dist *DiceDistribution) StdDev() float64 {
+	return math.Sqrt(dist.Variance())
+}
+
+// Percentile returns the smallest value whose cumulative probability reaches
+// percent, given between 0 and 100.
+func (dist *DiceDistribution) Percentile(percent float64) int {
+	target := percent / 100
+	cumulative := 0.0
+	for i, p := range dist.Probs {
+		cumulative += p
+		if cumulative >= target-DiceEpsilon {
+			return dist.Min + i
+		}
+	}
+
+	return dist.Max()
+}
+
+func (dist *DiceDistribution) Add(other *DiceDistribution) *DiceDistribution {
+	sum := &DiceDistribution{
+		Min:   dist.Min + other.Min,
+		Probs: make([]float64, len(dist.Probs)+len(other.Probs)-1),
+	}
+	for i, p := range dist.Probs {
+		if p == 0 {
+			continue
+		}
+		for j, q := range other.Probs {
+			sum.Probs[i+j] += p * q
+		}
+	}
+
+	return sum
+}
+
+func (dist *DiceDistribution) Negate() *DiceDistribution {
+	neg := &DiceDistribution{
+		Min:   -dist.Max(),
+		Probs: make([]float64, len(dist.Probs)),
+	}
+	for i, p := range dist.Probs {
+		neg.Probs[len(dist.Probs)-1-i] = p
+	}
+
+	return neg
+}
+
+func (dist *DiceDistribution) Shift(offset int) *DiceDistribution {
+	return &DiceDistribution{
+		Min:   dist.Min + offset,
+		Probs: append([]float64(nil), dist.Probs...),
+	}
+}
+
+// keepCost estimates the steps of keepDistribution: for each face, each count
+// of dice seen, each count of the face and each partial sum of the kept dice.
+func keepCost(die *DiceDistribution, n int, keep int) float64 {
+	faces := float64(len(die.Probs))
+
+	return faces * float64(n) * float64(n) / 2 * float64(keep) * faces
+}
+
+// keepDistribution walks the die faces from the best to the worst one, counting
+// how many of the n dice show each face and how many of those are kept.
+func keepDistribution(die *DiceDistribution, n int, keep int, low bool) *DiceDistribution {
+	type state [][]float64
+	size := keep*die.Max() + 1
+	if die.Min < 0 {
+		size = keep*(die.Max()-die.Min) + 1
+	}
+
+	newState := func() []state {
+		s := make([]state, n+1)
+		for m := range s {
+			s[m] = make(state, keep+1)
+		}
+		return s
+	}
+
+	dp := newState()
+	dp[0][0] = make([]float64, size)
+	dp[0][0][0] = 1
+
+	for i := range die.Probs {
+		idx := len(die.Probs) - 1 - i
+		if low {
+			idx = i
+		}
+		q := die.Probs[idx]
+		if q == 0 {
+			continue
+		}
+		v := die.Min + idx
+		if die.Min < 0 {
+			v -= die.Min
+		}
+
+		next := newState()
+		for m := 0; m <= n; m++ {
+			for j := 0; j <= keep; j++ {
+				sums := dp[m][j]
+				if sums == nil {
+					continue
+				}
+				w := 1.0
+				for c := 0; m+c <= n; c++ {
+					jj := j + c
+					if jj > keep {
+						jj = keep
+					}
+					if next[m+c][jj] == nil {
+						next[m+c][jj] = make([]float64, size)
+					}
+					for s, p := range sums {
+						if p != 0 {
+							next[m+c][jj][s+(jj-j)*v] += p * w
+						}
+					}
+					w *= float64(n-m-c) / float64(c+1) * q
+				}
+			}
+		}
+		dp = next
+	}
+
+	dist := &DiceDistribution{Probs: dp[n][keep]}
+	if die.Min < 0 {
+		dist.Min = keep * die.Min
+	}
+
+	return dist.trim()
+}
+
+func (dist *DiceDistribution) trim() *DiceDistribution {
+	start, end := 0, len(dist.Probs)
+	for start < end-1 && dist.Probs[start] == 0 {
+		start++
+	}
+	for end > start+1 && dist.Probs[end-1] == 0 {
+		end--
+	}
+
+	return &DiceDistribution{
+		Min:   dist.Min + start,
+		Probs: dist.Probs[start:end],
+	}
+}
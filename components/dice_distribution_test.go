@@ -0,0 +1,72 @@
+package components
+
+import (
+	"math"
+	"testing"
+)
+
+func assertNear(t *testing.T, name string, got float64, want float64, delta float64) {
+	t.Helper()
+	if math.Abs(got-want) > delta {
+		t.Errorf("%s is %g, want %g", name, got, want)
+	}
+}
+
+func TestDistribution(t *testing.T) {
+	dist := MustParseDices("2d6").Distribution()
+	if dist.Min != 2 || dist.Max() != 12 {
+		t.Fatalf("2d6 ranges from %d to %d", dist.Min, dist.Max())
+	}
+	assertNear(t, "P(7)", dist.P(7), 6.0/36, 1e-12)
+	assertNear(t, "P(2)", dist.P(2), 1.0/36, 1e-12)
+	assertNear(t, "P(13)", dist.P(13), 0, 0)
+	assertNear(t, "AtLeast(10)", dist.AtLeast(10), 6.0/36, 1e-12)
+	assertNear(t, "AtMost(4)", dist.AtMost(4), 6.0/36, 1e-12)
+	assertNear(t, "mean", dist.Mean(), 7, 1e-12)
+	assertNear(t, "variance", dist.Variance(), 35.0/6, 1e-12)
+	if p := dist.Percentile(50); p != 7 {
+		t.Errorf("median is %d, want 7", p)
+	}
+}
+
+func TestDistributionSumsToOne(t *testing.T) {
+	for _, notation := range []string{"3d6+2", "4d6kh3", "2d20kl1", "2d10!", "2d6ro2", "1d4-1d6", "d%"} {
+		sum := 0.0
+		for _, p := range MustParseDices(notation).Distribution().Probs {
+			sum += p
+		}
+		assertNear(t, notation+" total probability", sum, 1, 1e-9)
+	}
+}
+
+func TestKeepDistribution(t *testing.T) {
+	// Known means of keeping dice
+	assertNear(t, "4d6kh3 mean", MustParseDices("4d6kh3").Distribution().Mean(), 15869.0/1296, 1e-9)
+	assertNear(t, "2d20kh1 mean", MustParseDices("2d20kh1").Distribution().Mean(), 13.825, 1e-9)
+	assertNear(t, "2d20kl1 mean", MustParseDices("2d20kl1").Distribution().Mean(), 7.175, 1e-9)
+}
+
+func TestExplodeDistribution(t *testing.T) {
+	// An exploding die averages faces+1 / 2 * faces / (faces-1)
+	assertNear(t, "1d6! mean", MustParseDices("1d6!").Distribution().Mean(), 4.2, 1e-9)
+	if p := MustParseDices("1d6!").Distribution().P(6); p != 0 {
+		t.Errorf("1d6! shows 6 with %g, want 0", p)
+	}
+}
+
+func TestSampledDistribution(t *testing.T) {
+	d := MustParseDices("1000d6k999").Terms[0].Dices
+	dist := d.Distribution()
+	other := d.Distribution()
+	if dist.Min != other.Min || len(dist.Probs) != len(other.Probs) {
+		t.Fatalf("sampled distributions differ")
+	}
+	assertNear(t, "1000d6k999 mean", dist.Mean(), 3499, 10)
+}
+
+func TestDistributionWithoutFaces(t *testing.T) {
+	dist := (&Dices{X: 2}).Distribution()
+	if dist.Min != 0 || len(dist.Probs) != 1 {
+		t.Errorf("dices without faces are not a constant 0")
+	}
+}
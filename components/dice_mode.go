@@ -0,0 +1,83 @@
+package components
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+type RollMode int
+
+const (
+	RollNormal RollMode = iota
+	RollAdvantage
+	RollDisadvantage
+)
+
+func (rm RollMode) String() string {
+	switch rm {
+	case RollAdvantage:
+		return "advantage"
+	case RollDisadvantage:
+		return "disadvantage"
+	}
+
+	return "normal"
+}
+
+type DiceRoller interface {
+	RollDetailedWith(rnd *rand.Rand) *RollResult
+	Distribution() *DiceDistribution
+}
+
+// RollWithMode rolls twice with advantage or disadvantage and returns the best
+// or worst result, the other one is kept in Discarded for the combat log.
+func RollWithMode(roller DiceRoller, mode RollMode, rnd *rand.Rand) *RollResult {
+	result := roller.RollDetailedWith(rnd)
+	if mode == RollNormal {
+		return result
+	}
+
+	other := roller.RollDetailedWith(rnd)
+	if (mode == RollAdvantage) == (other.Total > result.Total) {
+		result, other = other, result
+	}
+	result.Mode = mode
+	result.Discarded = other
+
+	return result
+}
+
+// WithMode returns the distribution of the best (advantage) or worst
+// (disadvantage) of two independent rolls.
+func (dist *DiceDistribution) WithMode(mode RollMode) *DiceDistribution {
+	if mode == RollNormal {
+		return dist.Shift(0)
+	}
+
+	out := &DiceDistribution{
+		Min:   dist.Min,
+		Probs: make([]float64, len(dist.Probs)),
+	}
+	below, above := 0.0, 1.0
+	for i, p := range dist.Probs {
+		if mode == RollAdvantage {
+			// P(max = v) = F(v)² - F(v-1)²
+			out.Probs[i] = (below+p)*(below+p) - below*below
+		} else {
+			// P(min = v) = S(v)² - S(v+1)² with S the survival function
+			out.Probs[i] = above*above - (above-p)*(above-p)
+		}
+		below += p
+		above -= p
+	}
+
+	return out
+}
+
+func (rr *RollResult) modeString() string {
+	if rr.Discarded == nil {
+		return ""
+	}
+
+	return fmt.Sprintf(" (%s, %d discarded)", rr.Mode, rr.Discarded.Total)
+}
@@ -0,0 +1,276 @@
+package components
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+	"strings"
+)
+
+const DiceMaxCount = 1000
+
+type DiceTerm struct {
+	Sign  int
+	Dices *Dices
+	Flat  int
+}
+
+type DiceExpression struct {
+	Notation string
+	Terms    []DiceTerm
+}
+
+func (de *DiceExpression) Roll() float64 {
+	return float64(de.roll(GameRand).Total)
+}
+
+func (de *DiceExpression) RollDetailed() *RollResult {
+	return de.roll(GameRand)
+}
+
+func (de *DiceExpression) RollWith(rnd *rand.Rand) float64 {
+	return float64(de.roll(rnd).Total)
+}
+
+func (de *DiceExpression) RollDetailedWith(rnd *rand.Rand) *RollResult {
+	return de.roll(rnd)
+}
+
+func (de *DiceExpression) roll(rnd *rand.Rand) *RollResult {
+	groups := make([]DiceGroupResult, 0, len(de.Terms))
+	for _, t := range de.Terms {
+		if t.Dices == nil {
+			groups = append(groups, DiceGroupResult{
+				Sign:  t.Sign,
+				Total: t.Flat,
+			})
+			continue
+		}
+
+		group := t.Dices.roll(rnd)
+		group.Sign = t.Sign
+		groups = append(groups, group)
+	}
+
+	return newRollResult(de.String(), groups...)
+}
+
+func (de *DiceExpression) Modifier() int {
+	modifier := 0
+	for _, t := range de.Terms {
+		if t.Dices == nil {
+			modifier += t.Sign * t.Flat
+		}
+	}
+
+	return modifier
+}
+
+func (de *DiceExpression) String() string {
+	str := ""
+	for i, t := range de.Terms {
+		if t.Sign < 0 {
+			str += "-"
+		} else if i > 0 {
+			str += "+"
+		}
+
+		if t.Dices == nil {
+			str += fmt.Sprintf("%d", t.Flat)
+		} else {
+			str += t.Dices.String()
+		}
+	}
+
+	return str
+}
+
+// ParseDices reads tabletop dice notation such as "3d6+2", "4d6kh3", "4d6dl1",
+// "2d10!" or "d%". Terms are summed left to right, "kh"/"kl" keep the highest
+// or lowest dice, "dh"/"dl" drop them ("k" and "d" alone mean "kh" and "dl"),
+// "ro2" rerolls once any die showing 2 or less and "!" makes dice explode on
+// their highest face.
+func ParseDices(notation string) (*DiceExpression, error) {
+	p := diceParser{
+		src: strings.ToLower(strings.Join(strings.Fields(notation), "")),
+	}
+	expr := &DiceExpression{Notation: notation}
+
+	if p.src == "" {
+		return nil, fmt.Errorf("Dices:Parse - empty notation")
+	}
+
+	for !p.eof() {
+		sign := 1
+		switch p.peek() {
+		case '-':
+			sign = -1
+			p.pos++
+		case '+':
+			p.pos++
+		default:
+			if len(expr.Terms) > 0 {
+				return nil, p.errorf("expected '+' or '-'")
+			}
+		}
+
+		term, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		term.Sign = sign
+		expr.Terms = append(expr.Terms, term)
+	}
+
+	return expr, nil
+}
+
+func MustParseDices(notation string) *DiceExpression {
+	expr, err := ParseDices(notation)
+	if err != nil {
+		log.Fatalf("%s\n", err)
+	}
+
+	return expr
+}
+
+type diceParser struct {
+	src string
+	pos int
+}
+
+func (p *diceParser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *diceParser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+
+	return p.src[p.pos]
+}
+
+func (p *diceParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("Dices:Parse - %s at %d in %q", fmt.Sprintf(format, args...), p.pos, p.src)
+}
+
+func (p *diceParser) number() (int, bool) {
+	start := p.pos
+	n := 0
+	for !p.eof() && p.peek() >= '0' && p.peek() <= '9' {
+		n = n*10 + int(p.peek()-'0')
+		if n > 1_000_000 {
+			return 0, false
+		}
+		p.pos++
+	}
+
+	return n, p.pos > start
+}
+
+func (p *diceParser) term() (DiceTerm, error) {
+	x, hasX := p.number()
+	if p.peek() != 'd' {
+		if !hasX {
+			return DiceTerm{}, p.errorf("expected a number or dice")
+		}
+		return DiceTerm{Flat: x}, nil
+	}
+	p.pos++
+
+	if !hasX {
+		x = 1
+	}
+	if x < 1 || x > DiceMaxCount {
+		return DiceTerm{}, p.errorf("dice count must be between 1 and %d", DiceMaxCount)
+	}
+
+	d := &Dices{X: x}
+	if p.peek() == '%' {
+		d.Faces = 100
+		p.pos++
+	} else {
+		faces, ok := p.number()
+		if !ok || faces < 1 {
+			return DiceTerm{}, p.errorf("expected a number of faces")
+		}
+		d.Faces = faces
+	}
+
+	if err := p.modifiers(d); err != nil {
+		return DiceTerm{}, err
+	}
+
+	return DiceTerm{Dices: d}, nil
+}
+
+func (p *diceParser) modifiers(d *Dices) error {
+	for !p.eof() {
+		switch p.peek() {
+		case '!':
+			if d.Explode {
+				return p.errorf("dice already explode")
+			}
+			if d.Faces < 2 {
+				return p.errorf("d%d can not explode", d.Faces)
+			}
+			d.Explode = true
+			p.pos++
+		case 'r':
+			if d.Reroll > 0 {
+				return p.errorf("dice already reroll")
+			}
+			p.pos++
+			if p.peek() != 'o' {
+				return p.errorf("only reroll once (\"ro\") is supported")
+			}
+			p.pos++
+
+			n, ok := p.number()
+			if !ok || n < 1 || n >= d.Faces {
+				return p.errorf("can not reroll faces up to %d on d%d", n, d.Faces)
+			}
+			d.Reroll = n
+		case 'k', 'd':
+			if d.Keep > 0 {
+				return p.errorf("only one keep or drop is allowed")
+			}
+			drop := p.peek() == 'd'
+			p.pos++
+
+			low := drop
+			switch p.peek() {
+			case 'h':
+				low = false
+				p.pos++
+			case 'l':
+				low = true
+				p.pos++
+			}
+
+			n, ok := p.number()
+			if !ok {
+				return p.errorf("expected a number of dice to keep or drop")
+			}
+
+			if drop {
+				if n < 1 || n >= d.X {
+					return p.errorf("can not drop %d of %d dice", n, d.X)
+				}
+				d.Keep = d.X - n
+				d.KeepLow = !low
+			} else {
+				if n < 1 || n > d.X {
+					return p.errorf("can not keep %d of %d dice", n, d.X)
+				}
+				d.Keep = n
+				d.KeepLow = low
+			}
+		default:
+			return nil
+		}
+	}
+
+	return nil
+}
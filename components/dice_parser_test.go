@@ -0,0 +1,70 @@
+package components
+
+import "testing"
+
+func TestParseDices(t *testing.T) {
+	tests := []struct {
+		notation string
+		str      string
+		modifier int
+	}{
+		{"3d6+2", "3d6+2", 2},
+		{"d20", "1d20", 0},
+		{"d%", "1d100", 0},
+		{"4d6kh3", "4d6kh3", 0},
+		{"4d6dl1", "4d6kh3", 0},
+		{"4d6k3", "4d6kh3", 0},
+		{"2d20kl1", "2d20kl1", 0},
+		{"2d10!", "2d10!", 0},
+		{"2d6ro1", "2d6ro1", 0},
+		{" 1D8 - 1 ", "1d8-1", -1},
+		{"2d4+1d6+3-1", "2d4+1d6+3-1", 2},
+	}
+
+	for _, tt := range tests {
+		expr, err := ParseDices(tt.notation)
+		if err != nil {
+			t.Errorf("ParseDices(%q) - %s", tt.notation, err)
+			continue
+		}
+		if expr.String() != tt.str {
+			t.Errorf("ParseDices(%q) is %q, want %q", tt.notation, expr.String(), tt.str)
+		}
+		if expr.Modifier() != tt.modifier {
+			t.Errorf("ParseDices(%q) modifier is %d, want %d", tt.notation, expr.Modifier(), tt.modifier)
+		}
+	}
+}
+
+func TestParseDicesErrors(t *testing.T) {
+	for _, notation := range []string{
+		"",
+		"d",
+		"0d6",
+		"1001d6",
+		"3d6+",
+		"3d6*2",
+		"2d6k3",
+		"2d6dl2",
+		"2d6kh1kl1",
+		"1d1!",
+		"2d6!!",
+		"2d6r1",
+		"2d6ro6",
+		"2dx",
+	} {
+		if expr, err := ParseDices(notation); err == nil {
+			t.Errorf("ParseDices(%q) is %s, want an error", notation, expr)
+		}
+	}
+}
+
+func TestDiceExpressionRollRange(t *testing.T) {
+	rnd := NewRandStream("test", 1).Rand
+	expr := MustParseDices("2d6+3")
+	for i := 0; i < 1000; i++ {
+		if total := expr.RollWith(rnd); total < 5 || total > 15 {
+			t.Fatalf("2d6+3 rolled %g", total)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package components
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+	"strings"
+)
+
+// DicePool counts dice reaching Target instead of summing them, with Botch
+// every natural 1 cancels a success.
+type DicePool struct {
+	X      int
+	Faces  int
+	Target int
+	Botch  bool
+}
+
+type PoolResult struct {
+	Pool      *DicePool
+	Faces     []int
+	Successes int
+	Botches   int
+}
+
+func NewDicePool(faces int, x int, target int, botch bool) *DicePool {
+	if faces < 1 || x < 1 {
+		log.Fatalf("DicePool:New - can not roll %d dice of %d faces\n", x, faces)
+	}
+
+	return &DicePool{
+		X:      x,
+		Faces:  faces,
+		Target: target,
+		Botch:  botch,
+	}
+}
+
+func (dp *DicePool) Roll() *PoolResult {
+	return dp.RollWith(GameRand)
+}
+
+func (dp *DicePool) RollWith(rnd *rand.Rand) *PoolResult {
+	result := &PoolResult{
+		Pool:  dp,
+		Faces: make([]int, dp.X),
+	}
+
+	for i := range result.Faces {
+		face := rnd.Intn(dp.Faces) + 1
+		result.Faces[i] = face
+
+		if face >= dp.Target {
+			result.Successes++
+		} else if dp.Botch && face == 1 {
+			result.Botches++
+		}
+	}
+
+	return result
+}
+
+// Distribution returns the distribution of net successes, a constant 0 for
+// pools without faces or count.
+func (dp *DicePool) Distribution() *DiceDistribution {
+	if dp.Faces < 1 || dp.X < 1 {
+		return NewConstantDistribution(0)
+	}
+
+	f := float64(dp.Faces)
+	success := float64(dp.Faces-dp.Target+1) / f
+	if dp.Target > dp.Faces {
+		success = 0
+	} else if dp.Target <= 1 {
+		success = 1
+	}
+
+	botch := 0.0
+	if dp.Botch && dp.Target > 1 {
+		botch = 1 / f
+	}
+
+	die := &DiceDistribution{
+		Min:   -1,
+		Probs: []float64{botch, 1 - success - botch, success},
+	}
+
+	dist := NewConstantDistribution(0)
+	for i := 0; i < dp.X; i++ {
+		dist = dist.Add(die)
+	}
+
+	return dist.trim()
+}
+
+func (dp *DicePool) String() string {
+	str := fmt.Sprintf("%dd%d>=%d", dp.X, dp.Faces, dp.Target)
+	if dp.Botch {
+		str += " botch"
+	}
+
+	return str
+}
+
+func (pr *PoolResult) Net() int {
+	return pr.Successes - pr.Botches
+}
+
+// Botched reports a roll without any success and at least one natural 1.
+func (pr *PoolResult) Botched() bool {
+	return pr.Successes == 0 && pr.Botches > 0
+}
+
+func (pr *PoolResult) String() string {
+	faces := make([]string, len(pr.Faces))
+	for i, f := range pr.Faces {
+		faces[i] = fmt.Sprintf("%d", f)
+		if f >= pr.Pool.Target {
+			faces[i] += "*"
+		}
+	}
+
+	str := fmt.Sprintf("%s [%s] = %d successes", pr.Pool, strings.Join(faces, ","), pr.Net())
+	if pr.Botched() {
+		str += " (botch)"
+	}
+
+	return str
+}
@@ -0,0 +1,157 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+)
+
+type DieResult struct {
+	Value    int
+	Rolls    []int
+	Faces    int
+	Kept     bool
+	Rerolled int
+}
+
+func (dr DieResult) Exploded() bool {
+	return len(dr.Rolls) > 1
+}
+
+func (dr DieResult) Critical() bool {
+	return len(dr.Rolls) > 0 && dr.Rolls[0] == dr.Faces
+}
+
+func (dr DieResult) Fumble() bool {
+	return len(dr.Rolls) > 0 && dr.Rolls[0] == 1
+}
+
+func (dr DieResult) String() string {
+	rolls := make([]string, len(dr.Rolls))
+	for i, r := range dr.Rolls {
+		rolls[i] = fmt.Sprintf("%d", r)
+	}
+
+	str := strings.Join(rolls, "+")
+	if dr.Rerolled > 0 {
+		str = fmt.Sprintf("%dr%s", dr.Rerolled, str)
+	}
+	if !dr.Kept {
+		str = "(" + str + ")"
+	}
+
+	return str
+}
+
+// DiceGroupResult is the outcome of one term of an expression, Dices is nil for
+// a flat modifier.
+type DiceGroupResult struct {
+	Dices *Dices
+	Sign  int
+	Dice  []DieResult
+	Total int
+}
+
+func (dg DiceGroupResult) String() string {
+	if dg.Dices == nil {
+		return fmt.Sprintf("%d", dg.Total)
+	}
+
+	dice := make([]string, len(dg.Dice))
+	for i, d := range dg.Dice {
+		dice[i] = d.String()
+	}
+
+	return fmt.Sprintf("%s [%s]", dg.Dices, strings.Join(dice, ","))
+}
+
+type RollResult struct {
+	Notation  string
+	Groups    []DiceGroupResult
+	Modifier  int
+	Total     int
+	Mode      RollMode
+	Discarded *RollResult
+}
+
+func newRollResult(notation string, groups ...DiceGroupResult) *RollResult {
+	rr := &RollResult{
+		Notation: notation,
+		Groups:   groups,
+	}
+
+	for _, g := range groups {
+		if g.Dices == nil {
+			rr.Modifier += g.Sign * g.Total
+		}
+		rr.Total += g.Sign * g.Total
+	}
+
+	return rr
+}
+
+func (rr *RollResult) Kept() []DieResult {
+	kept := make([]DieResult, 0)
+	for _, g := range rr.Groups {
+		for _, d := range g.Dice {
+			if d.Kept {
+				kept = append(kept, d)
+			}
+		}
+	}
+
+	return kept
+}
+
+func (rr *RollResult) Dropped() []DieResult {
+	dropped := make([]DieResult, 0)
+	for _, g := range rr.Groups {
+		for _, d := range g.Dice {
+			if !d.Kept {
+				dropped = append(dropped, d)
+			}
+		}
+	}
+
+	return dropped
+}
+
+// Critical reports whether every kept die shows its highest face, as a natural
+// 20 on a d20.
+func (rr *RollResult) Critical() bool {
+	kept := rr.Kept()
+	for _, d := range kept {
+		if !d.Critical() {
+			return false
+		}
+	}
+
+	return len(kept) > 0
+}
+
+// Fumble reports whether every kept die shows a natural 1.
+func (rr *RollResult) Fumble() bool {
+	kept := rr.Kept()
+	for _, d := range kept {
+		if !d.Fumble() {
+			return false
+		}
+	}
+
+	return len(kept) > 0
+}
+
+// String renders the breakdown shown in combat logs, e.g. "3d6 [4,1,6]+2 = 13".
+// Dropped dice are wrapped in parentheses and exploded dice list every roll.
+func (rr *RollResult) String() string {
+	str := ""
+	for i, g := range rr.Groups {
+		if g.Sign < 0 {
+			str += "-"
+		} else if i > 0 {
+			str += "+"
+		}
+		str += g.String()
+	}
+
+	return fmt.Sprintf("%s = %d%s", str, rr.Total, rr.modeString())
+}
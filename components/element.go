@@ -0,0 +1,184 @@
+package components
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+)
+
+const (
+	// ElementPhysical is the element of damage without one
+	ElementPhysical = "physical"
+	// ElementPoison is the element of the poisoned status
+	ElementPoison = "poison"
+
+	AffinityNormal = "normal"
+	AffinityWeak   = "weak"
+	AffinityResist = "resist"
+	AffinityImmune = "immune"
+	AffinityAbsorb = "absorb"
+)
+
+var ElementsDir = filepath.Join(DataRoot, "elements")
+
+type Element struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// Resistance is a named matrix of damage multipliers by element: above 1 the
+// element is a weakness, below 1 a resistance, 0 an immunity and a negative
+// multiplier turns the damage into healing. Missing elements deal normal
+// damage.
+type Resistance struct {
+	Name   string             `json:"name"`
+	Values map[string]float64 `json:"values"`
+}
+
+// Affinity names the effect of a damage multiplier.
+func Affinity(multiplier float64) string {
+	switch {
+	case multiplier < 0:
+		return AffinityAbsorb
+	case multiplier == 0:
+		return AffinityImmune
+	case multiplier < 1:
+		return AffinityResist
+	case multiplier > 1:
+		return AffinityWeak
+	}
+
+	return AffinityNormal
+}
+
+// Elements holds the elements and the resistance matrices referring to them.
+type Elements struct {
+	elements    map[string]*Element
+	resistances map[string]*Resistance
+}
+
+func NewElements() *Elements {
+	return &Elements{
+		elements:    make(map[string]*Element),
+		resistances: make(map[string]*Resistance),
+	}
+}
+
+func (el *Elements) Add(element *Element) error {
+	if element.Name == "" {
+		return fmt.Errorf("Elements:Add - element has no name")
+	}
+	if el.elements[element.Name] != nil {
+		return fmt.Errorf("Elements:Add - element %s already exists", element.Name)
+	}
+
+	el.elements[element.Name] = element
+
+	return nil
+}
+
+// AddResistance adds a matrix, its elements must be added first.
+func (el *Elements) AddResistance(resistance *Resistance) error {
+	if resistance.Name == "" {
+		return fmt.Errorf("Elements:AddResistance - resistance has no name")
+	}
+	if el.resistances[resistance.Name] != nil {
+		return fmt.Errorf("Elements:AddResistance - resistance %s already exists", resistance.Name)
+	}
+	if err := el.Check(resistance.Values); err != nil {
+		return fmt.Errorf("Elements:AddResistance - resistance %s: %s", resistance.Name, err)
+	}
+
+	el.resistances[resistance.Name] = resistance
+
+	return nil
+}
+
+// Check fails on the first unknown element of values.
+func (el *Elements) Check(values map[string]float64) error {
+	for name := range values {
+		if el.elements[name] == nil {
+			return fmt.Errorf("unknown element %s", name)
+		}
+	}
+
+	return nil
+}
+
+func (el *Elements) Get(name string) *Element {
+	return el.elements[name]
+}
+
+func (el *Elements) Resistance(name string) *Resistance {
+	return el.resistances[name]
+}
+
+func (el *Elements) Names() []string {
+	names := make([]string, 0, len(el.elements))
+	for name := range el.elements {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func (el *Elements) ResistanceNames() []string {
+	names := make([]string, 0, len(el.resistances))
+	for name := range el.resistances {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Multiplier returns the damage multiplier of element against ge, its own
+// Resistances override the matrix named by its Resistance.
+func (el *Elements) Multiplier(ge *GameEntity, element string) float64 {
+	if element == "" {
+		element = ElementPhysical
+	}
+
+	if value, ok := ge.Resistances[element]; ok {
+		return value
+	}
+	if r := el.resistances[ge.Resistance]; r != nil {
+		if value, ok := r.Values[element]; ok {
+			return value
+		}
+	}
+
+	return 1
+}
+
+// Load reads a JSON object listing elements, then the resistance matrices.
+func (el *Elements) Load(data []byte) error {
+	var content struct {
+		Elements    []*Element    `json:"elements"`
+		Resistances []*Resistance `json:"resistances"`
+	}
+	if err := json.Unmarshal(data, &content); err != nil {
+		return fmt.Errorf("Elements:Load - %s", err)
+	}
+
+	for _, e := range content.Elements {
+		if err := el.Add(e); err != nil {
+			return err
+		}
+	}
+	for _, r := range content.Resistances {
+		if err := el.AddResistance(r); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (el *Elements) LoadDir(dir string) error {
+	return LoadDataDir(dir, func(file string, data []byte) error {
+		return el.Load(data)
+	})
+}
@@ -0,0 +1,73 @@
+package components
+
+import "testing"
+
+func newTestElements(t *testing.T) *Elements {
+	el := NewElements()
+	err := el.Load([]byte(`{
+		"elements": [{"name": "physical"}, {"name": "fire"}, {"name": "ice"}, {"name": "poison"}],
+		"resistances": [{"name": "slime", "values": {"physical": 0.5, "fire": 2, "ice": -1, "poison": 0}}]
+	}`))
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	return el
+}
+
+func TestElementsMultiplier(t *testing.T) {
+	el := newTestElements(t)
+	slime := &GameEntity{Name: "slime", Resistance: "slime"}
+
+	tests := []struct {
+		element    string
+		multiplier float64
+		affinity   string
+	}{
+		{"", 0.5, AffinityResist},
+		{ElementPhysical, 0.5, AffinityResist},
+		{"fire", 2, AffinityWeak},
+		{"ice", -1, AffinityAbsorb},
+		{ElementPoison, 0, AffinityImmune},
+		{"holy", 1, AffinityNormal},
+	}
+
+	for _, tt := range tests {
+		multiplier := el.Multiplier(slime, tt.element)
+		if multiplier != tt.multiplier {
+			t.Errorf("%q against slime is x%g, want x%g", tt.element, multiplier, tt.multiplier)
+		}
+		if affinity := Affinity(multiplier); affinity != tt.affinity {
+			t.Errorf("%q against slime is %s, want %s", tt.element, affinity, tt.affinity)
+		}
+	}
+}
+
+func TestElementsMultiplierOverride(t *testing.T) {
+	el := newTestElements(t)
+	ge := &GameEntity{Resistance: "slime", Resistances: map[string]float64{"fire": 0.5}}
+
+	if m := el.Multiplier(ge, "fire"); m != 0.5 {
+		t.Errorf("own resistance is x%g, want x0.5", m)
+	}
+	if m := el.Multiplier(ge, "ice"); m != -1 {
+		t.Errorf("matrix resistance is x%g, want x-1", m)
+	}
+	if m := el.Multiplier(&GameEntity{Resistance: "unknown"}, "fire"); m != 1 {
+		t.Errorf("unknown matrix is x%g, want x1", m)
+	}
+}
+
+func TestElementsLoadErrors(t *testing.T) {
+	for _, data := range []string{
+		`{"elements": [{"name": ""}]}`,
+		`{"elements": [{"name": "fire"}, {"name": "fire"}]}`,
+		`{"resistances": [{"name": "slime", "values": {"fire": 2}}]}`,
+		`{"elements": [{"name": "fire"}], "resistances": [{"name": ""}]}`,
+		`not json`,
+	} {
+		if err := NewElements().Load([]byte(data)); err == nil {
+			t.Errorf("loaded %s", data)
+		}
+	}
+}
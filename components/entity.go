@@ -3,7 +3,6 @@ package components
 import (
 	"fmt"
 	"github.com/EngoEngine/ecs"
-	"github.com/EngoEngine/engo"
 	"github.com/EngoEngine/engo/common"
 	"github.com/jinzhu/copier"
 	"log"
@@ -17,10 +16,12 @@ type Chained struct {
 	Prev *Entity
 }
 
+// Draggable holds the offset of the mouse from the position of an entity
+// being dragged. Clicking an entity IsDraggable drags it along its children,
+// unless its owner such as a menu claims the drag to move it itself.
 type Draggable struct {
 	XOff        float32
 	YOff        float32
-	Drag        bool
 	IsDraggable bool
 }
 
@@ -35,18 +36,6 @@ type Entity struct {
 	Refresh bool
 }
 
-func (e *Entity) StartDrag() {
-	e.XOff = engo.Input.Mouse.X - e.SpaceComponent.Position.X
-	e.YOff = engo.Input.Mouse.Y - e.SpaceComponent.Position.Y
-	e.Drag = true
-}
-
-func (e *Entity) StopDrag() {
-	e.Drag = false
-	e.XOff = 0
-	e.YOff = 0
-}
-
 func (e *Entity) Copy() *Entity {
 	cpy := Entity{}
 
@@ -0,0 +1,188 @@
+package components
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	EquipWeapon    = "weapon"
+	EquipShield    = "shield"
+	EquipHead      = "head"
+	EquipBody      = "body"
+	EquipAccessory = "accessory"
+)
+
+// EquipTypes are the valid Slot of an equipment Item.
+var EquipTypes = []string{EquipWeapon, EquipShield, EquipHead, EquipBody, EquipAccessory}
+
+// EquipSlot is a named slot of a GameEntity, it only accepts equipment whose
+// Slot matches its Type.
+type EquipSlot struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// DefaultEquipSlots are the slots of entities without their own Slots.
+var DefaultEquipSlots = []EquipSlot{
+	{Name: "weapon", Type: EquipWeapon},
+	{Name: "shield", Type: EquipShield},
+	{Name: "head", Type: EquipHead},
+	{Name: "body", Type: EquipBody},
+	{Name: "accessory-1", Type: EquipAccessory},
+	{Name: "accessory-2", Type: EquipAccessory},
+}
+
+var itemModifierTypes = map[string]ModifierType{
+	"":        ModifierFlat,
+	"flat":    ModifierFlat,
+	"percent": ModifierPercent,
+}
+
+// ItemModifier is a stat bonus granted while an equipment is worn, Type is
+// "flat" (default) or "percent".
+type ItemModifier struct {
+	Stat  string  `json:"stat"`
+	Type  string  `json:"type"`
+	Value float64 `json:"value"`
+}
+
+func (im *ItemModifier) Validate() error {
+	if !isStat(im.Stat) {
+		return fmt.Errorf("ItemModifier:Validate - unknown stat %s", im.Stat)
+	}
+	if _, ok := itemModifierTypes[im.Type]; !ok {
+		return fmt.Errorf("ItemModifier:Validate - unknown modifier type %s", im.Type)
+	}
+
+	return nil
+}
+
+// NewModifiers returns the stat modifiers of an equipment, all from source.
+func (i *Item) NewModifiers(source string) []*Modifier {
+	modifiers := make([]*Modifier, 0, len(i.Modifiers))
+	for _, im := range i.Modifiers {
+		modifiers = append(modifiers, &Modifier{
+			Stat:   im.Stat,
+			Type:   itemModifierTypes[im.Type],
+			Value:  im.Value,
+			Source: source,
+		})
+	}
+
+	return modifiers
+}
+
+func isEquipType(slot string) bool {
+	for _, t := range EquipTypes {
+		if t == slot {
+			return true
+		}
+	}
+
+	return false
+}
+
+// EquipSource is the Modifier Source of the equipment worn in slot.
+func EquipSource(slot string) string {
+	return "equipment:" + slot
+}
+
+func (ge *GameEntity) EquipSlots() []EquipSlot {
+	if len(ge.Slots) > 0 {
+		return ge.Slots
+	}
+
+	return DefaultEquipSlots
+}
+
+func (ge *GameEntity) EquipSlot(name string) *EquipSlot {
+	for _, s := range ge.EquipSlots() {
+		if s.Name == name {
+			return &s
+		}
+	}
+
+	return nil
+}
+
+// CanEquip checks that ge has slot and that it accepts item.
+func (ge *GameEntity) CanEquip(item *Item, slot string) error {
+	if item.Kind != ItemEquipment {
+		return fmt.Errorf("GameEntity:CanEquip - %s is not an equipment", item.Name)
+	}
+
+	s := ge.EquipSlot(slot)
+	if s == nil {
+		return fmt.Errorf("GameEntity:CanEquip - %s has no slot %s", ge.Name, slot)
+	}
+	if s.Type != item.Slot {
+		return fmt.Errorf("GameEntity:CanEquip - slot %s of %s only accepts %s, not %s", slot, ge.Name, s.Type, item.Name)
+	}
+
+	return nil
+}
+
+// SlotFor returns the first empty slot of ge accepting item, or the first
+// accepting one when they are all used.
+func (ge *GameEntity) SlotFor(item *Item) (string, error) {
+	slot := ""
+	for _, s := range ge.EquipSlots() {
+		if s.Type != item.Slot {
+			continue
+		}
+		if ge.Equipment[s.Name] == nil {
+			return s.Name, nil
+		}
+		if slot == "" {
+			slot = s.Name
+		}
+	}
+
+	if slot == "" {
+		return "", fmt.Errorf("GameEntity:SlotFor - %s has no slot for %s", ge.Name, item.Name)
+	}
+
+	return slot, nil
+}
+
+// StatDelta is the change of a stat when swapping an equipment.
+type StatDelta struct {
+	Stat   string
+	Before float64
+	After  float64
+}
+
+func (sd StatDelta) Delta() float64 {
+	return sd.After - sd.Before
+}
+
+func (sd StatDelta) String() string {
+	return fmt.Sprintf("%s %.6g -> %.6g (%+.6g)", sd.Stat, sd.Before, sd.After, sd.Delta())
+}
+
+// Compare returns the stats of ge that would change if item replaced the
+// equipment in slot, nil item comparing with an empty slot.
+func (ge *GameEntity) Compare(item *Item, slot string) []StatDelta {
+	deltas := make([]StatDelta, 0)
+	if ge.Stats == nil {
+		return deltas
+	}
+
+	stats := ge.Stats.Copy()
+	stats.RemoveSource(EquipSource(slot))
+	if item != nil {
+		for _, m := range item.NewModifiers(EquipSource(slot)) {
+			stats.AddModifier(m)
+		}
+	}
+
+	for _, stat := range StatNames {
+		before, after := ge.Stats.Get(stat), stats.Get(stat)
+		if math.Abs(after-before) > 1e-9 {
+			deltas = append(deltas, StatDelta{Stat: stat, Before: before, After: after})
+		}
+	}
+
+	return deltas
+}
@@ -12,7 +12,6 @@ type Event struct {
 	Data       map[string]any
 	Dispatched int
 	Listened   bool
-	Active     bool
 }
 
 func (e Event) Type() string {
@@ -0,0 +1,157 @@
+package components
+
+import (
+	"log"
+	"math"
+	"math/rand"
+)
+
+// PrdMinChance is the lowest chance of a Prd, below it the increment is too
+// small to be told apart from 0.
+const PrdMinChance = 0.0001
+
+var prdConstants = make(map[float64]float64)
+
+// ShuffleBag hands out every item once per cycle in a random order, then
+// refills and shuffles itself again.
+type ShuffleBag[T any] struct {
+	Items []T
+	Rand  *rand.Rand
+	next  []T
+}
+
+func NewShuffleBag[T any](rnd *rand.Rand, items ...T) *ShuffleBag[T] {
+	if rnd == nil {
+		rnd = GameRand
+	}
+
+	return &ShuffleBag[T]{
+		Items: items,
+		Rand:  rnd,
+	}
+}
+
+// Next draws the next item, false when the bag has no items.
+func (sb *ShuffleBag[T]) Next() (T, bool) {
+	if len(sb.next) == 0 {
+		sb.Refill()
+	}
+	if len(sb.next) == 0 {
+		var zero T
+		return zero, false
+	}
+
+	item := sb.next[len(sb.next)-1]
+	sb.next = sb.next[:len(sb.next)-1]
+
+	return item, true
+}
+
+func (sb *ShuffleBag[T]) Refill() {
+	sb.next = append(sb.next[:0], sb.Items...)
+	sb.Rand.Shuffle(len(sb.next), func(i, j int) {
+		sb.next[i], sb.next[j] = sb.next[j], sb.next[i]
+	})
+}
+
+func (sb *ShuffleBag[T]) Remaining() int {
+	return len(sb.next)
+}
+
+// NewChanceBag builds a bag of true/false outcomes where hits out of size are
+// true, a 25% crit chance over 8 draws is NewChanceBag(rnd, 2, 8).
+func NewChanceBag(rnd *rand.Rand, hits int, size int) *ShuffleBag[bool] {
+	items := make([]bool, size)
+	for i := 0; i < hits && i < size; i++ {
+		items[i] = true
+	}
+
+	return NewShuffleBag(rnd, items...)
+}
+
+// Prd is a pseudo-random distribution: the chance of success starts at C and
+// grows by C after every failure, resetting on success. C is chosen so that
+// the average success rate matches the nominal chance, with far fewer streaks
+// and droughts than independent rolls.
+type Prd struct {
+	Chance float64
+	C      float64
+	Rand   *rand.Rand
+	Misses int
+}
+
+func NewPrd(rnd *rand.Rand, chance float64) *Prd {
+	if chance > 0 && chance < PrdMinChance {
+		log.Fatalf("Prd:New - chance %g is below %g\n", chance, PrdMinChance)
+	}
+	if rnd == nil {
+		rnd = GameRand
+	}
+
+	return &Prd{
+		Chance: chance,
+		C:      PrdConstant(chance),
+		Rand:   rnd,
+	}
+}
+
+func (p *Prd) Current() float64 {
+	return math.Min(1, p.C*float64(p.Misses+1))
+}
+
+func (p *Prd) Roll() bool {
+	if p.Rand.Float64() < p.Current() {
+		p.Misses = 0
+		return true
+	}
+
+	p.Misses++
+
+	return false
+}
+
+func (p *Prd) Reset() {
+	p.Misses = 0
+}
+
+// PrdConstant finds by bisection the increment C whose average success rate
+// equals chance, constants are cached by chance.
+func PrdConstant(chance float64) float64 {
+	if chance <= 0 {
+		return 0
+	}
+	if chance >= 1 {
+		return 1
+	}
+	if c, ok := prdConstants[chance]; ok {
+		return c
+	}
+
+	low, high := 0.0, chance
+	for i := 0; i < 64; i++ {
+		c := (low + high) / 2
+		if prdChance(c) > chance {
+			high = c
+		} else {
+			low = c
+		}
+	}
+	prdConstants[chance] = (low + high) / 2
+
+	return prdConstants[chance]
+}
+
+// prdChance returns the average success rate of increment c, the inverse of
+// the expected number of trials until a success. Trials after the chance of
+// failing them all drops below DiceEpsilon are ignored.
+func prdChance(c float64) float64 {
+	expected := 0.0
+	fail := 1.0
+	for n := 1; fail > DiceEpsilon; n++ {
+		p := math.Min(1, c*float64(n))
+		expected += float64(n) * fail * p
+		fail *= 1 - p
+	}
+
+	return 1 / expected
+}
@@ -0,0 +1,21 @@
+package components
+
+// FloatingText is a label rising above Target until its Age reaches Duration.
+type FloatingText struct {
+	Entity   *Entity
+	Target   *GameEntity
+	Age      float32
+	Duration float32
+}
+
+func (ft *FloatingText) Alpha() float32 {
+	if ft.Duration <= 0 || ft.Age >= ft.Duration {
+		return 0
+	}
+
+	return 1 - ft.Age/ft.Duration
+}
+
+func (ft *FloatingText) Done() bool {
+	return ft.Age >= ft.Duration
+}
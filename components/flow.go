@@ -0,0 +1,11 @@
+package components
+
+const (
+	FlowTitle  = "title"
+	FlowField  = "field"
+	FlowBattle = "battle"
+
+	FlowStart     = "start"
+	FlowEncounter = "encounter"
+	FlowLeave     = "leave"
+)
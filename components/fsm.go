@@ -0,0 +1,176 @@
+package components
+
+import (
+	"fmt"
+	"log"
+)
+
+const (
+	// FsmAny matches every state in the From of a transition
+	FsmAny = "*"
+	// FsmBack is the transition name reported when going back in history
+	FsmBack = "back"
+
+	FsmHistorySize = 32
+)
+
+// FsmHook runs when leaving or entering a state.
+type FsmHook func(from string, to string)
+
+// FsmGuard allows a transition when it returns true.
+type FsmGuard func() bool
+
+type FsmState struct {
+	Name    string
+	OnEnter FsmHook
+	OnExit  FsmHook
+}
+
+type FsmTransition struct {
+	Name  string
+	From  []string
+	To    string
+	Guard FsmGuard
+}
+
+func (t *FsmTransition) from(state string) bool {
+	for _, f := range t.From {
+		if f == state || f == FsmAny {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Fsm is a finite state machine. Transitions are fired by name from one of
+// their From states, the left states are kept in History so Back can return
+// to them. OnChange is called after every change.
+type Fsm struct {
+	Name        string
+	Current     string
+	History     []string
+	OnChange    func(fsm *Fsm, transition string, from string, to string)
+	states      map[string]*FsmState
+	transitions map[string][]*FsmTransition
+}
+
+func NewFsm(name string, initial string) *Fsm {
+	fsm := &Fsm{
+		Name:        name,
+		Current:     initial,
+		History:     make([]string, 0),
+		states:      make(map[string]*FsmState),
+		transitions: make(map[string][]*FsmTransition),
+	}
+	fsm.AddState(initial, nil, nil)
+
+	return fsm
+}
+
+// AddState adds or replaces a state and its hooks.
+func (fsm *Fsm) AddState(name string, enter FsmHook, exit FsmHook) *Fsm {
+	fsm.states[name] = &FsmState{
+		Name:    name,
+		OnEnter: enter,
+		OnExit:  exit,
+	}
+
+	return fsm
+}
+
+// AddTransition adds a transition between known states, a name can be used by
+// several transitions with different From states.
+func (fsm *Fsm) AddTransition(name string, from []string, to string, guard FsmGuard) *Fsm {
+	for _, state := range append([]string{to}, from...) {
+		if state != FsmAny && fsm.states[state] == nil {
+			log.Fatalf("Fsm:AddTransition - %s transition %s uses unknown state %s\n", fsm.Name, name, state)
+		}
+	}
+
+	fsm.transitions[name] = append(fsm.transitions[name], &FsmTransition{
+		Name:  name,
+		From:  from,
+		To:    to,
+		Guard: guard,
+	})
+
+	return fsm
+}
+
+func (fsm *Fsm) Is(state string) bool {
+	return fsm.Current == state
+}
+
+func (fsm *Fsm) transition(name string) *FsmTransition {
+	for _, t := range fsm.transitions[name] {
+		if t.from(fsm.Current) {
+			return t
+		}
+	}
+
+	return nil
+}
+
+// Can tells whether the transition can be fired from the current state.
+func (fsm *Fsm) Can(name string) bool {
+	t := fsm.transition(name)
+	return t != nil && (t.Guard == nil || t.Guard())
+}
+
+func (fsm *Fsm) Fire(name string) error {
+	t := fsm.transition(name)
+	if t == nil {
+		return fmt.Errorf("Fsm:Fire - %s has no transition %s from %s", fsm.Name, name, fsm.Current)
+	}
+	if t.Guard != nil && !t.Guard() {
+		return fmt.Errorf("Fsm:Fire - %s transition %s from %s is guarded", fsm.Name, name, fsm.Current)
+	}
+
+	fsm.change(name, t.To, true)
+
+	return nil
+}
+
+// Back returns to the previous state of the history, without guards.
+func (fsm *Fsm) Back() error {
+	if len(fsm.History) == 0 {
+		return fmt.Errorf("Fsm:Back - %s has no history", fsm.Name)
+	}
+
+	previous := fsm.History[len(fsm.History)-1]
+	fsm.History = fsm.History[:len(fsm.History)-1]
+	fsm.change(FsmBack, previous, false)
+
+	return nil
+}
+
+func (fsm *Fsm) Previous() string {
+	if len(fsm.History) == 0 {
+		return ""
+	}
+
+	return fsm.History[len(fsm.History)-1]
+}
+
+func (fsm *Fsm) change(name string, to string, record bool) {
+	from := fsm.Current
+	if s := fsm.states[from]; s != nil && s.OnExit != nil {
+		s.OnExit(from, to)
+	}
+
+	if record {
+		fsm.History = append(fsm.History, from)
+		if len(fsm.History) > FsmHistorySize {
+			fsm.History = fsm.History[1:]
+		}
+	}
+	fsm.Current = to
+
+	if s := fsm.states[to]; s != nil && s.OnEnter != nil {
+		s.OnEnter(from, to)
+	}
+	if fsm.OnChange != nil {
+		fsm.OnChange(fsm, name, from, to)
+	}
+}
@@ -0,0 +1,98 @@
+package components
+
+import "testing"
+
+func newTestFsm(open *bool) *Fsm {
+	return NewFsm("door", "closed").
+		AddState("open", func(from string, to string) {
+			*open = true
+		}, func(from string, to string) {
+			*open = false
+		}).
+		AddState("locked", nil, nil).
+		AddTransition("open", []string{"closed"}, "open", nil).
+		AddTransition("close", []string{"open"}, "closed", nil).
+		AddTransition("lock", []string{"closed"}, "locked", nil).
+		AddTransition("break", []string{FsmAny}, "open", nil)
+}
+
+func TestFsmFire(t *testing.T) {
+	open := false
+	fsm := newTestFsm(&open)
+
+	if err := fsm.Fire("close"); err == nil {
+		t.Errorf("closed door closed again")
+	}
+	if err := fsm.Fire("open"); err != nil {
+		t.Fatalf("%s", err)
+	}
+	if !fsm.Is("open") || !open {
+		t.Errorf("door is %s, entered %t", fsm.Current, open)
+	}
+	if err := fsm.Fire("close"); err != nil {
+		t.Fatalf("%s", err)
+	}
+	if open {
+		t.Errorf("door closed without leaving open")
+	}
+}
+
+func TestFsmGuard(t *testing.T) {
+	key := false
+	fsm := NewFsm("door", "locked").
+		AddState("closed", nil, nil).
+		AddTransition("unlock", []string{"locked"}, "closed", func() bool {
+			return key
+		})
+
+	if fsm.Can("unlock") || fsm.Fire("unlock") == nil {
+		t.Errorf("door unlocked without key")
+	}
+	key = true
+	if !fsm.Can("unlock") || fsm.Fire("unlock") != nil {
+		t.Errorf("door not unlocked with key")
+	}
+}
+
+func TestFsmAnyAndHistory(t *testing.T) {
+	open := false
+	fsm := newTestFsm(&open)
+	changes := 0
+	fsm.OnChange = func(fsm *Fsm, transition string, from string, to string) {
+		changes++
+	}
+
+	if err := fsm.Fire("lock"); err != nil {
+		t.Fatalf("%s", err)
+	}
+	if err := fsm.Fire("break"); err != nil {
+		t.Fatalf("%s", err)
+	}
+	if fsm.Previous() != "locked" {
+		t.Errorf("previous state is %s, want locked", fsm.Previous())
+	}
+	if err := fsm.Back(); err != nil || !fsm.Is("locked") || open {
+		t.Errorf("back to %s, open %t", fsm.Current, open)
+	}
+	if err := fsm.Back(); err != nil || !fsm.Is("closed") {
+		t.Errorf("back to %s", fsm.Current)
+	}
+	if err := fsm.Back(); err == nil {
+		t.Errorf("went back without history")
+	}
+	if changes != 4 {
+		t.Errorf("%d changes, want 4", changes)
+	}
+}
+
+func TestFsmHistorySize(t *testing.T) {
+	open := false
+	fsm := newTestFsm(&open)
+	for i := 0; i < FsmHistorySize; i++ {
+		fsm.Fire("open")
+		fsm.Fire("close")
+	}
+	if len(fsm.History) != FsmHistorySize {
+		t.Errorf("history holds %d states, want %d", len(fsm.History), FsmHistorySize)
+	}
+}
@@ -9,11 +9,24 @@ import (
 type Targets []*GameEntity
 
 type GameEntity struct {
-	Name    string
-	Type    string
-	SubType string
-	Sprite  string
-	Entity  *Entity
+	Name        string
+	Type        string
+	SubType     string
+	Sprite      string
+	Entity      *Entity
+	Stats       *Stats
+	Statuses    []*StatusEffect
+	Abilities   []string
+	Progression string
+	Level       int
+	Xp          int
+	Ai          string
+	Behaviour   string
+	Resistance  string
+	Resistances map[string]float64
+	Inventory   *Inventory
+	Slots       []EquipSlot
+	Equipment   map[string]*Item
 }
 
 func (ge *GameEntity) Copy() *GameEntity {
@@ -24,5 +37,28 @@ func (ge *GameEntity) Copy() *GameEntity {
 		log.Fatalf(fmt.Sprintf("GameEntity:Copy - Failed to copy %s : %s\n", ge.Name, err))
 	}
 
+	if ge.Stats != nil {
+		cpy.Stats = ge.Stats.Copy()
+	}
+
+	cpy.Abilities = append([]string(nil), ge.Abilities...)
+	if ge.Inventory != nil {
+		cpy.Inventory = ge.Inventory.Copy()
+	}
+	cpy.Slots = append([]EquipSlot(nil), ge.Slots...)
+	cpy.Resistances = make(map[string]float64, len(ge.Resistances))
+	for element, value := range ge.Resistances {
+		cpy.Resistances[element] = value
+	}
+	cpy.Equipment = make(map[string]*Item, len(ge.Equipment))
+	for slot, item := range ge.Equipment {
+		cpy.Equipment[slot] = item
+	}
+	cpy.Statuses = make([]*StatusEffect, 0, len(ge.Statuses))
+	for _, s := range ge.Statuses {
+		sc := *s
+		cpy.Statuses = append(cpy.Statuses, &sc)
+	}
+
 	return &cpy
 }
@@ -0,0 +1,285 @@
+package components
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+)
+
+const (
+	ItemConsumable = "consumable"
+	ItemEquipment  = "equipment"
+	ItemKey        = "key"
+
+	ItemDefaultStack = 99
+)
+
+var ItemsDir = filepath.Join(DataRoot, "items")
+
+// Item is an item definition. Consumables resolve their Use ability when
+// used, equipment goes in a slot of its Slot type and grants its Modifiers
+// while worn, key items can be neither used nor dropped. Stack is the number
+// of items per inventory slot, 99 for consumables and 1 for others by default.
+type Item struct {
+	Name        string         `json:"name"`
+	Kind        string         `json:"kind"`
+	Description string         `json:"description"`
+	Stack       int            `json:"stack"`
+	Value       int            `json:"value"`
+	Slot        string         `json:"slot"`
+	Modifiers   []ItemModifier `json:"modifiers"`
+	Use         *Ability       `json:"use"`
+}
+
+func (i *Item) Validate() error {
+	if i.Name == "" {
+		return fmt.Errorf("Item:Validate - item has no name")
+	}
+	if i.Stack < 0 {
+		return fmt.Errorf("Item:Validate - item %s has a negative stack", i.Name)
+	}
+
+	switch i.Kind {
+	case ItemConsumable:
+		if i.Use == nil {
+			return fmt.Errorf("Item:Validate - consumable %s has no use", i.Name)
+		}
+		if i.Use.Name == "" {
+			i.Use.Name = i.Name
+		}
+		if err := i.Use.Validate(); err != nil {
+			return fmt.Errorf("Item:Validate - item %s: %s", i.Name, err)
+		}
+	case ItemEquipment:
+		if i.Slot == "" {
+			return fmt.Errorf("Item:Validate - equipment %s has no slot", i.Name)
+		}
+		if !isEquipType(i.Slot) {
+			return fmt.Errorf("Item:Validate - equipment %s has unknown slot %s", i.Name, i.Slot)
+		}
+		for _, m := range i.Modifiers {
+			if err := m.Validate(); err != nil {
+				return fmt.Errorf("Item:Validate - equipment %s: %s", i.Name, err)
+			}
+		}
+	case ItemKey:
+	default:
+		return fmt.Errorf("Item:Validate - item %s has unknown kind %s", i.Name, i.Kind)
+	}
+
+	return nil
+}
+
+func (i *Item) MaxStack() int {
+	if i.Stack > 0 {
+		return i.Stack
+	}
+	if i.Kind == ItemConsumable {
+		return ItemDefaultStack
+	}
+
+	return 1
+}
+
+func (i *Item) Usable() bool {
+	return i.Kind == ItemConsumable
+}
+
+type ItemStack struct {
+	Item  *Item
+	Count int
+}
+
+func (is *ItemStack) String() string {
+	if is.Count == 1 {
+		return is.Item.Name
+	}
+
+	return fmt.Sprintf("%s x%d", is.Item.Name, is.Count)
+}
+
+// Inventory holds stacks of items in pickup order, Size limits the number of
+// stacks (unlimited when 0). Party members can share the same Inventory.
+type Inventory struct {
+	Stacks []*ItemStack
+	Size   int
+}
+
+func NewInventory(size int) *Inventory {
+	return &Inventory{
+		Stacks: make([]*ItemStack, 0),
+		Size:   size,
+	}
+}
+
+// Add fills the existing stacks of item then new ones, and returns how many
+// items fit, none for a count below 1.
+func (inv *Inventory) Add(item *Item, count int) int {
+	if count <= 0 {
+		return 0
+	}
+
+	added := 0
+	for _, s := range inv.Stacks {
+		if s.Item != item || count == 0 {
+			continue
+		}
+
+		n := minInt(count, item.MaxStack()-s.Count)
+		s.Count += n
+		count -= n
+		added += n
+	}
+
+	for count > 0 && (inv.Size == 0 || len(inv.Stacks) < inv.Size) {
+		n := minInt(count, item.MaxStack())
+		inv.Stacks = append(inv.Stacks, &ItemStack{Item: item, Count: n})
+		count -= n
+		added += n
+	}
+
+	return added
+}
+
+// Remove takes up to count items named name, from the last stacks first, and
+// returns how many were removed.
+func (inv *Inventory) Remove(name string, count int) int {
+	removed := 0
+	for i := len(inv.Stacks) - 1; i >= 0 && count > 0; i-- {
+		s := inv.Stacks[i]
+		if s.Item.Name != name {
+			continue
+		}
+
+		n := minInt(count, s.Count)
+		s.Count -= n
+		count -= n
+		removed += n
+		if s.Count == 0 {
+			inv.Stacks = append(inv.Stacks[:i], inv.Stacks[i+1:]...)
+		}
+	}
+
+	return removed
+}
+
+func (inv *Inventory) Get(name string) *Item {
+	for _, s := range inv.Stacks {
+		if s.Item.Name == name {
+			return s.Item
+		}
+	}
+
+	return nil
+}
+
+func (inv *Inventory) Count(name string) int {
+	count := 0
+	for _, s := range inv.Stacks {
+		if s.Item.Name == name {
+			count += s.Count
+		}
+	}
+
+	return count
+}
+
+// Usable returns one stack per usable item, totaling the count of its stacks.
+func (inv *Inventory) Usable() []*ItemStack {
+	usable := make([]*ItemStack, 0)
+	for _, s := range inv.Stacks {
+		if !s.Item.Usable() {
+			continue
+		}
+
+		merged := false
+		for _, u := range usable {
+			if u.Item == s.Item {
+				u.Count += s.Count
+				merged = true
+			}
+		}
+		if !merged {
+			usable = append(usable, &ItemStack{Item: s.Item, Count: s.Count})
+		}
+	}
+
+	return usable
+}
+
+func (inv *Inventory) Copy() *Inventory {
+	cpy := NewInventory(inv.Size)
+	for _, s := range inv.Stacks {
+		cpy.Stacks = append(cpy.Stacks, &ItemStack{Item: s.Item, Count: s.Count})
+	}
+
+	return cpy
+}
+
+type Items struct {
+	items map[string]*Item
+}
+
+func NewItems() *Items {
+	return &Items{
+		items: make(map[string]*Item),
+	}
+}
+
+func (it *Items) Add(item *Item) error {
+	if err := item.Validate(); err != nil {
+		return err
+	}
+	if it.items[item.Name] != nil {
+		return fmt.Errorf("Items:Add - item %s already exists", item.Name)
+	}
+
+	it.items[item.Name] = item
+
+	return nil
+}
+
+func (it *Items) Get(name string) *Item {
+	return it.items[name]
+}
+
+func (it *Items) Names() []string {
+	names := make([]string, 0, len(it.items))
+	for name := range it.items {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Load reads a JSON array of items.
+func (it *Items) Load(data []byte) error {
+	var items []*Item
+	if err := json.Unmarshal(data, &items); err != nil {
+		return fmt.Errorf("Items:Load - %s", err)
+	}
+
+	for _, i := range items {
+		if err := it.Add(i); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (it *Items) LoadDir(dir string) error {
+	return LoadDataDir(dir, func(file string, data []byte) error {
+		return it.Load(data)
+	})
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,61 @@
+package components
+
+import "testing"
+
+func TestInventoryAdd(t *testing.T) {
+	potion := &Item{Name: "Potion", Kind: ItemConsumable, Stack: 5}
+	inv := NewInventory(2)
+
+	if n := inv.Add(potion, 7); n != 7 {
+		t.Errorf("added %d potions, want 7", n)
+	}
+	if n := inv.Add(potion, 5); n != 3 {
+		t.Errorf("added %d potions to a full inventory, want 3", n)
+	}
+	if len(inv.Stacks) != 2 || inv.Count("Potion") != 10 {
+		t.Errorf("%d stacks, %d potions", len(inv.Stacks), inv.Count("Potion"))
+	}
+
+	for _, count := range []int{0, -3} {
+		if n := inv.Add(potion, count); n != 0 {
+			t.Errorf("added %d potions for %d", n, count)
+		}
+	}
+	for _, s := range inv.Stacks {
+		if s.Count < 0 {
+			t.Errorf("stack of %d potions", s.Count)
+		}
+	}
+}
+
+func TestInventoryRemove(t *testing.T) {
+	potion := &Item{Name: "Potion", Kind: ItemConsumable, Stack: 5}
+	sword := &Item{Name: "Sword", Kind: ItemEquipment, Slot: EquipWeapon}
+	inv := NewInventory(0)
+	inv.Add(potion, 7)
+	inv.Add(sword, 1)
+
+	if n := inv.Remove("Potion", 3); n != 3 || inv.Count("Potion") != 4 {
+		t.Errorf("removed %d potions, %d left", n, inv.Count("Potion"))
+	}
+	if n := inv.Remove("Potion", 10); n != 4 || inv.Get("Potion") != nil {
+		t.Errorf("removed %d potions, %d left", n, inv.Count("Potion"))
+	}
+	if n := inv.Remove("Potion", 1); n != 0 {
+		t.Errorf("removed %d missing potions", n)
+	}
+	if len(inv.Stacks) != 1 || inv.Get("Sword") != sword {
+		t.Errorf("sword stack lost")
+	}
+}
+
+func TestInventoryUsable(t *testing.T) {
+	inv := NewInventory(0)
+	inv.Add(&Item{Name: "Potion", Kind: ItemConsumable}, 1)
+	inv.Add(&Item{Name: "Key", Kind: ItemKey}, 1)
+
+	usable := inv.Usable()
+	if len(usable) != 1 || usable[0].Item.Name != "Potion" {
+		t.Errorf("usable items are %v", usable)
+	}
+}
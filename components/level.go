@@ -0,0 +1,257 @@
+package components
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"path/filepath"
+	"sort"
+)
+
+const (
+	CurveLinear     = "linear"
+	CurvePolynomial = "polynomial"
+	CurveTable      = "table"
+
+	LevelMax = 99
+)
+
+var ProgressionsDir = filepath.Join(DataRoot, "progressions")
+
+// Curve gives the total XP needed to reach a level: Base * (level - 1) when
+// linear, Base * (level - 1) ^ Exponent when polynomial, or Table[level - 2]
+// for a table. Max caps the level, LevelMax by default.
+type Curve struct {
+	Type     string  `json:"type"`
+	Base     float64 `json:"base"`
+	Exponent float64 `json:"exponent"`
+	Table    []int   `json:"table"`
+	Max      int     `json:"max"`
+}
+
+func (c *Curve) Validate() error {
+	switch c.Type {
+	case CurveLinear:
+		if c.Base <= 0 {
+			return fmt.Errorf("Curve:Validate - linear curve needs a positive base")
+		}
+	case CurvePolynomial:
+		if c.Base <= 0 || c.Exponent <= 0 {
+			return fmt.Errorf("Curve:Validate - polynomial curve needs a positive base and exponent")
+		}
+	case CurveTable:
+		if len(c.Table) == 0 {
+			return fmt.Errorf("Curve:Validate - table curve is empty")
+		}
+		for i, xp := range c.Table {
+			if xp <= 0 || (i > 0 && xp <= c.Table[i-1]) {
+				return fmt.Errorf("Curve:Validate - table curve must be positive and increasing")
+			}
+		}
+	default:
+		return fmt.Errorf("Curve:Validate - unknown curve type %s", c.Type)
+	}
+
+	if c.Max < 0 {
+		return fmt.Errorf("Curve:Validate - negative max level")
+	}
+
+	return nil
+}
+
+func (c *Curve) MaxLevel() int {
+	max := LevelMax
+	if c.Max > 0 {
+		max = c.Max
+	}
+	if c.Type == CurveTable && len(c.Table)+1 < max {
+		max = len(c.Table) + 1
+	}
+
+	return max
+}
+
+// XpFor returns the total XP needed to reach level.
+func (c *Curve) XpFor(level int) int {
+	if level <= 1 {
+		return 0
+	}
+	if level > c.MaxLevel() {
+		level = c.MaxLevel()
+	}
+
+	switch c.Type {
+	case CurveLinear:
+		return int(math.Round(c.Base * float64(level-1)))
+	case CurvePolynomial:
+		return int(math.Round(c.Base * math.Pow(float64(level-1), c.Exponent)))
+	case CurveTable:
+		return c.Table[level-2]
+	}
+
+	return 0
+}
+
+// LevelFor returns the level reached with a total of xp.
+func (c *Curve) LevelFor(xp int) int {
+	level := 1
+	for level < c.MaxLevel() && xp >= c.XpFor(level+1) {
+		level++
+	}
+
+	return level
+}
+
+// Progression is a leveling definition. Growth holds the dice notation rolled
+// for each stat on level-up, a plain number for a fixed gain, and Abilities
+// the abilities learnt at a given level.
+type Progression struct {
+	Name      string            `json:"name"`
+	Curve     Curve             `json:"curve"`
+	Growth    map[string]string `json:"growth"`
+	Abilities map[int][]string  `json:"abilities"`
+	growth    map[string]*DiceExpression
+}
+
+func (p *Progression) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("Progression:Validate - progression has no name")
+	}
+	if err := p.Curve.Validate(); err != nil {
+		return fmt.Errorf("Progression:Validate - progression %s: %s", p.Name, err)
+	}
+
+	p.growth = make(map[string]*DiceExpression, len(p.Growth))
+	for stat, notation := range p.Growth {
+		if !isStat(stat) {
+			return fmt.Errorf("Progression:Validate - progression %s grows unknown stat %s", p.Name, stat)
+		}
+		dice, err := ParseDices(notation)
+		if err != nil {
+			return fmt.Errorf("Progression:Validate - progression %s stat %s: %s", p.Name, stat, err)
+		}
+		p.growth[stat] = dice
+	}
+
+	for level := range p.Abilities {
+		if level < 1 || level > p.Curve.MaxLevel() {
+			return fmt.Errorf("Progression:Validate - progression %s unlocks abilities at unreachable level %d", p.Name, level)
+		}
+	}
+
+	return nil
+}
+
+// LevelGain lists what an entity gained when reaching Level.
+type LevelGain struct {
+	GameEntity *GameEntity
+	Level      int
+	Stats      map[string]float64
+	Abilities  []string
+}
+
+// Grow rolls the stat gains and lists the abilities unlocked at level, stats
+// are rolled in StatNames order so a seeded rnd always gives the same gains.
+func (p *Progression) Grow(ge *GameEntity, level int, rnd *rand.Rand) *LevelGain {
+	if p.growth == nil {
+		p.Validate()
+	}
+
+	gain := &LevelGain{
+		GameEntity: ge,
+		Level:      level,
+		Stats:      make(map[string]float64),
+		Abilities:  make([]string, 0),
+	}
+	for _, stat := range StatNames {
+		if dice := p.growth[stat]; dice != nil {
+			gain.Stats[stat] = dice.RollWith(rnd)
+		}
+	}
+
+	for _, name := range p.Abilities[level] {
+		if !ge.Knows(name) {
+			gain.Abilities = append(gain.Abilities, name)
+		}
+	}
+
+	return gain
+}
+
+// Unlocked returns every ability learnt up to level.
+func (p *Progression) Unlocked(level int) []string {
+	levels := make([]int, 0, len(p.Abilities))
+	for l := range p.Abilities {
+		if l <= level {
+			levels = append(levels, l)
+		}
+	}
+	sort.Ints(levels)
+
+	unlocked := make([]string, 0)
+	for _, l := range levels {
+		unlocked = append(unlocked, p.Abilities[l]...)
+	}
+
+	return unlocked
+}
+
+type Progressions struct {
+	progressions map[string]*Progression
+}
+
+func NewProgressions() *Progressions {
+	return &Progressions{
+		progressions: make(map[string]*Progression),
+	}
+}
+
+func (pr *Progressions) Add(progression *Progression) error {
+	if err := progression.Validate(); err != nil {
+		return err
+	}
+	if pr.progressions[progression.Name] != nil {
+		return fmt.Errorf("Progressions:Add - progression %s already exists", progression.Name)
+	}
+
+	pr.progressions[progression.Name] = progression
+
+	return nil
+}
+
+func (pr *Progressions) Get(name string) *Progression {
+	return pr.progressions[name]
+}
+
+func (pr *Progressions) Names() []string {
+	names := make([]string, 0, len(pr.progressions))
+	for name := range pr.progressions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Load reads a JSON array of progressions.
+func (pr *Progressions) Load(data []byte) error {
+	var progressions []*Progression
+	if err := json.Unmarshal(data, &progressions); err != nil {
+		return fmt.Errorf("Progressions:Load - %s", err)
+	}
+
+	for _, p := range progressions {
+		if err := pr.Add(p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (pr *Progressions) LoadDir(dir string) error {
+	return LoadDataDir(dir, func(file string, data []byte) error {
+		return pr.Load(data)
+	})
+}
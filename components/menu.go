@@ -2,6 +2,17 @@ package components
 
 import "github.com/EngoEngine/engo/common"
 
+const (
+	MenuOpen     = "open"
+	MenuHidden   = "hidden"
+	MenuDragging = "dragging"
+
+	MenuShow = "show"
+	MenuHide = "hide"
+	MenuDrag = "drag"
+	MenuDrop = "drop"
+)
+
 type Menu struct {
 	common.SpaceComponent
 	Name      string
@@ -10,4 +21,5 @@ type Menu struct {
 	Selected  int
 	Disabled  EntityArray
 	Font      *common.Font
+	State     *Fsm
 }
@@ -0,0 +1,312 @@
+package components
+
+import "math"
+
+const StreamNoise = "noise"
+
+var noiseGrad3 = [12][3]float64{
+	{1, 1, 0}, {-1, 1, 0}, {1, -1, 0}, {-1, -1, 0},
+	{1, 0, 1}, {-1, 0, 1}, {1, 0, -1}, {-1, 0, -1},
+	{0, 1, 1}, {0, -1, 1}, {0, 1, -1}, {0, -1, -1},
+}
+
+// Noise generates coherent noise in [-1, 1] from a permutation table built out
+// of Seed, the same seed always gives the same values.
+type Noise struct {
+	Seed int64
+	perm [512]int
+}
+
+func NewNoise(seed int64) *Noise {
+	n := &Noise{Seed: seed}
+
+	// The permutation is shuffled with RandSource rather than math/rand so it
+	// does not depend on the Go release.
+	source := &RandSource{}
+	source.Seed(seed)
+	for i := 0; i < 256; i++ {
+		n.perm[i] = i
+	}
+	for i := 255; i > 0; i-- {
+		j := int(source.Uint64() % uint64(i+1))
+		n.perm[i], n.perm[j] = n.perm[j], n.perm[i]
+	}
+	for i := 0; i < 256; i++ {
+		n.perm[i+256] = n.perm[i]
+	}
+
+	return n
+}
+
+// NewGameNoise derives a named noise layer, e.g. "terrain" or "clouds", from
+// the game master seed.
+func NewGameNoise(name string) *Noise {
+	return NewNoise(GameStreams.Get(StreamNoise + ":" + name).Seed)
+}
+
+func (n *Noise) hash(i int) int {
+	return n.perm[i&255]
+}
+
+func (n *Noise) Value1D(x float64) float64 {
+	i := int(math.Floor(x))
+	t := noiseFade(x - float64(i))
+
+	return noiseLerp(t, n.lattice(n.hash(i)), n.lattice(n.hash(i+1)))
+}
+
+func (n *Noise) Value2D(x float64, y float64) float64 {
+	i, j := int(math.Floor(x)), int(math.Floor(y))
+	u, v := noiseFade(x-float64(i)), noiseFade(y-float64(j))
+
+	a := n.hash(i) + j
+	b := n.hash(i+1) + j
+
+	return noiseLerp(v,
+		noiseLerp(u, n.lattice(n.hash(a)), n.lattice(n.hash(b))),
+		noiseLerp(u, n.lattice(n.hash(a+1)), n.lattice(n.hash(b+1))),
+	)
+}
+
+func (n *Noise) Value3D(x float64, y float64, z float64) float64 {
+	i, j, k := int(math.Floor(x)), int(math.Floor(y)), int(math.Floor(z))
+	u, v, w := noiseFade(x-float64(i)), noiseFade(y-float64(j)), noiseFade(z-float64(k))
+
+	a, b := n.hash(i)+j, n.hash(i+1)+j
+	aa, ab, ba, bb := n.hash(a)+k, n.hash(a+1)+k, n.hash(b)+k, n.hash(b+1)+k
+
+	return noiseLerp(w,
+		noiseLerp(v,
+			noiseLerp(u, n.lattice(n.hash(aa)), n.lattice(n.hash(ba))),
+			noiseLerp(u, n.lattice(n.hash(ab)), n.lattice(n.hash(bb))),
+		),
+		noiseLerp(v,
+			noiseLerp(u, n.lattice(n.hash(aa+1)), n.lattice(n.hash(ba+1))),
+			noiseLerp(u, n.lattice(n.hash(ab+1)), n.lattice(n.hash(bb+1))),
+		),
+	)
+}
+
+func (n *Noise) Perlin1D(x float64) float64 {
+	i := int(math.Floor(x))
+	x -= float64(i)
+	u := noiseFade(x)
+
+	return 2 * noiseLerp(u, noiseGrad1(n.hash(i), x), noiseGrad1(n.hash(i+1), x-1))
+}
+
+func (n *Noise) Perlin2D(x float64, y float64) float64 {
+	return n.Perlin3D(x, y, 0)
+}
+
+// Perlin3D is Ken Perlin's improved noise.
+func (n *Noise) Perlin3D(x float64, y float64, z float64) float64 {
+	i, j, k := int(math.Floor(x)), int(math.Floor(y)), int(math.Floor(z))
+	x, y, z = x-float64(i), y-float64(j), z-float64(k)
+	u, v, w := noiseFade(x), noiseFade(y), noiseFade(z)
+
+	a, b := n.hash(i)+j, n.hash(i+1)+j
+	aa, ab, ba, bb := n.hash(a)+k, n.hash(a+1)+k, n.hash(b)+k, n.hash(b+1)+k
+
+	return noiseLerp(w,
+		noiseLerp(v,
+			noiseLerp(u, noiseGrad3D(n.hash(aa), x, y, z), noiseGrad3D(n.hash(ba), x-1, y, z)),
+			noiseLerp(u, noiseGrad3D(n.hash(ab), x, y-1, z), noiseGrad3D(n.hash(bb), x-1, y-1, z)),
+		),
+		noiseLerp(v,
+			noiseLerp(u, noiseGrad3D(n.hash(aa+1), x, y, z-1), noiseGrad3D(n.hash(ba+1), x-1, y, z-1)),
+			noiseLerp(u, noiseGrad3D(n.hash(ab+1), x, y-1, z-1), noiseGrad3D(n.hash(bb+1), x-1, y-1, z-1)),
+		),
+	)
+}
+
+func (n *Noise) Simplex1D(x float64) float64 {
+	return n.Simplex2D(x, 0)
+}
+
+func (n *Noise) Simplex2D(x float64, y float64) float64 {
+	const f2 = 0.36602540378443865 // (sqrt(3) - 1) / 2
+	const g2 = 0.21132486540518713 // (3 - sqrt(3)) / 6
+
+	s := (x + y) * f2
+	i, j := int(math.Floor(x+s)), int(math.Floor(y+s))
+	t := float64(i+j) * g2
+	x0, y0 := x-(float64(i)-t), y-(float64(j)-t)
+
+	i1, j1 := 0, 1
+	if x0 > y0 {
+		i1, j1 = 1, 0
+	}
+
+	x1, y1 := x0-float64(i1)+g2, y0-float64(j1)+g2
+	x2, y2 := x0-1+2*g2, y0-1+2*g2
+
+	ii, jj := i&255, j&255
+	g0 := n.perm[ii+n.perm[jj]] % 12
+	g1 := n.perm[ii+i1+n.perm[jj+j1]] % 12
+	g3 := n.perm[ii+1+n.perm[jj+1]] % 12
+
+	return 70 * (noiseCorner(g0, 0.5, x0, y0, 0) + noiseCorner(g1, 0.5, x1, y1, 0) + noiseCorner(g3, 0.5, x2, y2, 0))
+}
+
+func (n *Noise) Simplex3D(x float64, y float64, z float64) float64 {
+	const f3 = 1.0 / 3
+	const g3 = 1.0 / 6
+
+	s := (x + y + z) * f3
+	i, j, k := int(math.Floor(x+s)), int(math.Floor(y+s)), int(math.Floor(z+s))
+	t := float64(i+j+k) * g3
+	x0, y0, z0 := x-(float64(i)-t), y-(float64(j)-t), z-(float64(k)-t)
+
+	// Find which of the six tetrahedra of the cube holds the point
+	var i1, j1, k1, i2, j2, k2 int
+	if x0 >= y0 {
+		if y0 >= z0 {
+			i1, j1, k1, i2, j2, k2 = 1, 0, 0, 1, 1, 0
+		} else if x0 >= z0 {
+			i1, j1, k1, i2, j2, k2 = 1, 0, 0, 1, 0, 1
+		} else {
+			i1, j1, k1, i2, j2, k2 = 0, 0, 1, 1, 0, 1
+		}
+	} else {
+		if y0 < z0 {
+			i1, j1, k1, i2, j2, k2 = 0, 0, 1, 0, 1, 1
+		} else if x0 < z0 {
+			i1, j1, k1, i2, j2, k2 = 0, 1, 0, 0, 1, 1
+		} else {
+			i1, j1, k1, i2, j2, k2 = 0, 1, 0, 1, 1, 0
+		}
+	}
+
+	x1, y1, z1 := x0-float64(i1)+g3, y0-float64(j1)+g3, z0-float64(k1)+g3
+	x2, y2, z2 := x0-float64(i2)+2*g3, y0-float64(j2)+2*g3, z0-float64(k2)+2*g3
+	x3, y3, z3 := x0-1+3*g3, y0-1+3*g3, z0-1+3*g3
+
+	ii, jj, kk := i&255, j&255, k&255
+	c0 := n.perm[ii+n.perm[jj+n.perm[kk]]] % 12
+	c1 := n.perm[ii+i1+n.perm[jj+j1+n.perm[kk+k1]]] % 12
+	c2 := n.perm[ii+i2+n.perm[jj+j2+n.perm[kk+k2]]] % 12
+	c3 := n.perm[ii+1+n.perm[jj+1+n.perm[kk+1]]] % 12
+
+	return 32 * (noiseCorner(c0, 0.6, x0, y0, z0) + noiseCorner(c1, 0.6, x1, y1, z1) +
+		noiseCorner(c2, 0.6, x2, y2, z2) + noiseCorner(c3, 0.6, x3, y3, z3))
+}
+
+func (n *Noise) lattice(h int) float64 {
+	return float64(h)/127.5 - 1
+}
+
+// Fractal sums octaves of a noise function, each one Lacunarity times finer
+// and Gain times weaker than the previous one.
+type Fractal struct {
+	Octaves    int
+	Lacunarity float64
+	Gain       float64
+}
+
+func NewFractal(octaves int) Fractal {
+	return Fractal{
+		Octaves:    octaves,
+		Lacunarity: 2,
+		Gain:       0.5,
+	}
+}
+
+func (f Fractal) Fbm1D(noise func(x float64) float64, x float64) float64 {
+	return f.sum(func(freq float64) float64 {
+		return noise(x * freq)
+	})
+}
+
+func (f Fractal) Fbm2D(noise func(x, y float64) float64, x float64, y float64) float64 {
+	return f.sum(func(freq float64) float64 {
+		return noise(x*freq, y*freq)
+	})
+}
+
+func (f Fractal) Fbm3D(noise func(x, y, z float64) float64, x float64, y float64, z float64) float64 {
+	return f.sum(func(freq float64) float64 {
+		return noise(x*freq, y*freq, z*freq)
+	})
+}
+
+// Ridged2D folds every octave into sharp crests, useful for mountains.
+func (f Fractal) Ridged2D(noise func(x, y float64) float64, x float64, y float64) float64 {
+	return f.sum(func(freq float64) float64 {
+		return 1 - 2*math.Abs(noise(x*freq, y*freq))
+	})
+}
+
+func (f Fractal) sum(octave func(freq float64) float64) float64 {
+	total, amplitude, freq, norm := 0.0, 1.0, 1.0, 0.0
+	for i := 0; i < f.Octaves; i++ {
+		total += octave(freq) * amplitude
+		norm += amplitude
+		amplitude *= f.Gain
+		freq *= f.Lacunarity
+	}
+
+	if norm == 0 {
+		return 0
+	}
+
+	return total / norm
+}
+
+// NoiseRange maps a noise value from [-1, 1] to [min, max].
+func NoiseRange(value float64, min float64, max float64) float64 {
+	value = math.Max(-1, math.Min(1, value))
+
+	return min + (value+1)/2*(max-min)
+}
+
+func noiseFade(t float64) float64 {
+	return t * t * t * (t*(t*6-15) + 10)
+}
+
+func noiseLerp(t float64, a float64, b float64) float64 {
+	return a + t*(b-a)
+}
+
+func noiseGrad1(h int, x float64) float64 {
+	if h&1 == 0 {
+		return x
+	}
+
+	return -x
+}
+
+func noiseGrad3D(h int, x float64, y float64, z float64) float64 {
+	h &= 15
+	u := y
+	if h < 8 {
+		u = x
+	}
+
+	v := z
+	if h < 4 {
+		v = y
+	} else if h == 12 || h == 14 {
+		v = x
+	}
+
+	if h&1 != 0 {
+		u = -u
+	}
+	if h&2 != 0 {
+		v = -v
+	}
+
+	return u + v
+}
+
+func noiseCorner(g int, radius float64, x float64, y float64, z float64) float64 {
+	t := radius - x*x - y*y - z*z
+	if t < 0 {
+		return 0
+	}
+	t *= t
+	grad := noiseGrad3[g]
+
+	return t * t * (grad[0]*x + grad[1]*y + grad[2]*z)
+}
@@ -0,0 +1,239 @@
+package components
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+)
+
+const PrefabMaxDepth = 16
+
+var PrefabsDir = filepath.Join(DataRoot, "prefabs")
+
+// Prefab is a named GameEntity template. A prefab extending another one
+// overrides its non-empty fields, stats, resistances and equipment slots, and
+// adds to its abilities. Resistance names an element matrix, Resistances
+// override some of its values.
+type Prefab struct {
+	Name        string             `json:"name"`
+	Extends     string             `json:"extends"`
+	Type        string             `json:"type"`
+	SubType     string             `json:"subtype"`
+	Sprite      string             `json:"sprite"`
+	Width       float32            `json:"width"`
+	Height      float32            `json:"height"`
+	Stats       map[string]float64 `json:"stats"`
+	Abilities   []string           `json:"abilities"`
+	Slots       []EquipSlot        `json:"slots"`
+	Progression string             `json:"progression"`
+	Level       int                `json:"level"`
+	Ai          string             `json:"ai"`
+	Behaviour   string             `json:"behaviour"`
+	Resistance  string             `json:"resistance"`
+	Resistances map[string]float64 `json:"resistances"`
+}
+
+type Prefabs struct {
+	prefabs map[string]*Prefab
+}
+
+func NewPrefabs() *Prefabs {
+	return &Prefabs{
+		prefabs: make(map[string]*Prefab),
+	}
+}
+
+func (p *Prefabs) Add(prefab *Prefab) error {
+	if prefab.Name == "" {
+		return fmt.Errorf("Prefabs:Add - prefab has no name")
+	}
+	if p.prefabs[prefab.Name] != nil {
+		return fmt.Errorf("Prefabs:Add - prefab %s already exists", prefab.Name)
+	}
+	for stat := range prefab.Stats {
+		if !isStat(stat) {
+			return fmt.Errorf("Prefabs:Add - prefab %s has unknown stat %s", prefab.Name, stat)
+		}
+	}
+	if prefab.Level < 0 {
+		return fmt.Errorf("Prefabs:Add - prefab %s has a negative level", prefab.Name)
+	}
+	for _, s := range prefab.Slots {
+		if !isEquipType(s.Type) {
+			return fmt.Errorf("Prefabs:Add - prefab %s slot %s has unknown type %s", prefab.Name, s.Name, s.Type)
+		}
+	}
+
+	p.prefabs[prefab.Name] = prefab
+
+	return nil
+}
+
+func (p *Prefabs) Get(name string) *Prefab {
+	return p.prefabs[name]
+}
+
+func (p *Prefabs) Names() []string {
+	names := make([]string, 0, len(p.prefabs))
+	for name := range p.prefabs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Load reads a JSON array of prefabs.
+func (p *Prefabs) Load(data []byte) error {
+	var prefabs []*Prefab
+	if err := json.Unmarshal(data, &prefabs); err != nil {
+		return fmt.Errorf("Prefabs:Load - %s", err)
+	}
+
+	for _, prefab := range prefabs {
+		if err := p.Add(prefab); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *Prefabs) LoadDir(dir string) error {
+	err := LoadDataDir(dir, func(file string, data []byte) error {
+		return p.Load(data)
+	})
+	if err != nil {
+		return err
+	}
+
+	return p.Validate()
+}
+
+// Validate checks that every parent exists and that no prefab extends itself.
+func (p *Prefabs) Validate() error {
+	for _, name := range p.Names() {
+		if _, err := p.Resolve(name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Resolve flattens the inheritance chain of a prefab into a single one.
+func (p *Prefabs) Resolve(name string) (*Prefab, error) {
+	chain := make([]*Prefab, 0)
+	for current := name; current != ""; {
+		prefab := p.prefabs[current]
+		if prefab == nil {
+			if len(chain) == 0 {
+				return nil, fmt.Errorf("Prefabs:Resolve - unknown prefab %s", current)
+			}
+			return nil, fmt.Errorf("Prefabs:Resolve - prefab %s extends unknown prefab %s", chain[len(chain)-1].Name, current)
+		}
+		if len(chain) > PrefabMaxDepth {
+			return nil, fmt.Errorf("Prefabs:Resolve - prefab %s inheritance is cyclic or too deep", name)
+		}
+
+		chain = append(chain, prefab)
+		current = prefab.Extends
+	}
+
+	resolved := &Prefab{
+		Name:        name,
+		Stats:       make(map[string]float64),
+		Abilities:   make([]string, 0),
+		Resistances: make(map[string]float64),
+	}
+	for i := len(chain) - 1; i >= 0; i-- {
+		resolved.merge(chain[i])
+	}
+
+	return resolved, nil
+}
+
+func (p *Prefab) merge(child *Prefab) {
+	if child.Type != "" {
+		p.Type = child.Type
+	}
+	if child.SubType != "" {
+		p.SubType = child.SubType
+	}
+	if child.Sprite != "" {
+		p.Sprite = child.Sprite
+	}
+	if child.Width != 0 {
+		p.Width = child.Width
+	}
+	if child.Height != 0 {
+		p.Height = child.Height
+	}
+	if child.Progression != "" {
+		p.Progression = child.Progression
+	}
+	if child.Level != 0 {
+		p.Level = child.Level
+	}
+	if child.Ai != "" {
+		p.Ai = child.Ai
+	}
+	if child.Behaviour != "" {
+		p.Behaviour = child.Behaviour
+	}
+	if child.Resistance != "" {
+		p.Resistance = child.Resistance
+	}
+	if len(child.Slots) > 0 {
+		p.Slots = child.Slots
+	}
+	for stat, value := range child.Stats {
+		p.Stats[stat] = value
+	}
+	for element, value := range child.Resistances {
+		p.Resistances[element] = value
+	}
+
+	for _, a := range child.Abilities {
+		known := false
+		for _, k := range p.Abilities {
+			if k == a {
+				known = true
+				break
+			}
+		}
+		if !known {
+			p.Abilities = append(p.Abilities, a)
+		}
+	}
+}
+
+// NewGameEntity creates an entity of the prefab, without its sprite Entity.
+func (p *Prefabs) NewGameEntity(name string) (*GameEntity, error) {
+	prefab, err := p.Resolve(name)
+	if err != nil {
+		return nil, err
+	}
+
+	level := prefab.Level
+	if level == 0 {
+		level = 1
+	}
+
+	return &GameEntity{
+		Name:        prefab.Name,
+		Type:        prefab.Type,
+		SubType:     prefab.SubType,
+		Sprite:      prefab.Sprite,
+		Stats:       NewStats(prefab.Stats),
+		Abilities:   prefab.Abilities,
+		Slots:       append([]EquipSlot(nil), prefab.Slots...),
+		Progression: prefab.Progression,
+		Level:       level,
+		Ai:          prefab.Ai,
+		Behaviour:   prefab.Behaviour,
+		Resistance:  prefab.Resistance,
+		Resistances: prefab.Resistances,
+	}, nil
+}
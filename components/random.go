@@ -1,9 +1,153 @@
 package components
 
 import (
+	"hash/fnv"
 	"math/rand"
 	"time"
 )
 
+const (
+	StreamGame      = "game"
+	StreamCombat    = "combat"
+	StreamLoot      = "loot"
+	StreamAi        = "ai"
+	StreamBehaviour = "behaviour"
+	StreamLevel     = "level"
+)
+
 var GameSeed = time.Now().UnixNano()
-var GameRand = rand.New(rand.NewSource(GameSeed))
+var GameStreams = NewRandStreams(GameSeed)
+var GameRand = GameStreams.Get(StreamGame).Rand
+
+// Reseed reseeds GameStreams and keeps GameSeed in sync.
+func Reseed(seed int64) {
+	GameSeed = seed
+	GameStreams.Reseed(seed)
+}
+
+// RandSource is a splitmix64 generator, its whole state is a single uint64 so
+// it can be saved and restored, unlike the math/rand default source.
+type RandSource struct {
+	State uint64
+}
+
+func (rs *RandSource) Seed(seed int64) {
+	rs.State = uint64(seed)
+}
+
+func (rs *RandSource) Uint64() uint64 {
+	rs.State += 0x9e3779b97f4a7c15
+	z := rs.State
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+
+	return z ^ (z >> 31)
+}
+
+func (rs *RandSource) Int63() int64 {
+	return int64(rs.Uint64() >> 1)
+}
+
+type RandStream struct {
+	*rand.Rand
+	Name   string
+	Seed   int64
+	source *RandSource
+}
+
+func NewRandStream(name string, seed int64) *RandStream {
+	source := &RandSource{}
+	source.Seed(seed)
+
+	return &RandStream{
+		Rand:   rand.New(source),
+		Name:   name,
+		Seed:   seed,
+		source: source,
+	}
+}
+
+func (rs *RandStream) State() uint64 {
+	return rs.source.State
+}
+
+func (rs *RandStream) Restore(state uint64) {
+	rs.source.State = state
+}
+
+func (rs *RandStream) Reset() {
+	rs.source.Seed(rs.Seed)
+}
+
+type RandSnapshot struct {
+	Seed    int64
+	Streams map[string]uint64
+}
+
+type RandStreams struct {
+	Seed    int64
+	streams map[string]*RandStream
+}
+
+func NewRandStreams(seed int64) *RandStreams {
+	return &RandStreams{
+		Seed:    seed,
+		streams: make(map[string]*RandStream),
+	}
+}
+
+// Get returns the named stream, creating it on first use with a seed derived
+// from the master seed and the name so streams never share a sequence.
+func (rss *RandStreams) Get(name string) *RandStream {
+	if rss.streams[name] == nil {
+		rss.streams[name] = NewRandStream(name, rss.derive(name))
+	}
+
+	return rss.streams[name]
+}
+
+func (rss *RandStreams) Names() []string {
+	names := make([]string, 0, len(rss.streams))
+	for name := range rss.streams {
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// Reseed derives every existing stream again from a new master seed, streams
+// are reset in place so references held by consumers stay valid.
+func (rss *RandStreams) Reseed(seed int64) {
+	rss.Seed = seed
+	for name, stream := range rss.streams {
+		stream.Seed = rss.derive(name)
+		stream.Reset()
+	}
+}
+
+func (rss *RandStreams) Snapshot() RandSnapshot {
+	snapshot := RandSnapshot{
+		Seed:    rss.Seed,
+		Streams: make(map[string]uint64, len(rss.streams)),
+	}
+	for name, stream := range rss.streams {
+		snapshot.Streams[name] = stream.State()
+	}
+
+	return snapshot
+}
+
+func (rss *RandStreams) Restore(snapshot RandSnapshot) {
+	rss.Reseed(snapshot.Seed)
+	for name, state := range snapshot.Streams {
+		rss.Get(name).Restore(state)
+	}
+}
+
+func (rss *RandStreams) derive(name string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(name))
+	source := RandSource{State: uint64(rss.Seed) ^ h.Sum64()}
+
+	return int64(source.Uint64())
+}
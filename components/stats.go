@@ -0,0 +1,316 @@
+package components
+
+import (
+	"math"
+	"sort"
+)
+
+const (
+	StatHp  = "HP"
+	StatMp  = "MP"
+	StatAtk = "ATK"
+	StatDef = "DEF"
+	StatMag = "MAG"
+	StatRes = "RES"
+	StatSpd = "SPD"
+	StatLck = "LCK"
+)
+
+// PoolStats are the stats with a current value, e.g. HP is the maximum and
+// Pool(StatHp) the remaining hit points.
+var PoolStats = []string{StatHp, StatMp}
+
+var StatNames = []string{StatHp, StatMp, StatAtk, StatDef, StatMag, StatRes, StatSpd, StatLck}
+
+type ModifierType int
+
+const (
+	ModifierFlat ModifierType = iota
+	ModifierPercent
+	ModifierOverride
+	ModifierClamp
+)
+
+type StackRule int
+
+const (
+	// StackAlways adds every application as a new modifier
+	StackAlways StackRule = iota
+	// StackRefresh replaces the modifier with the same ID
+	StackRefresh
+	// StackHighest keeps only the strongest modifier with the same ID
+	StackHighest
+	// StackLimit adds up to MaxStacks modifiers with the same ID, then refreshes them
+	StackLimit
+)
+
+// Modifier changes a stat, Value is added for flat, a percentage of the flat
+// total for percent, or replaces it for override. Clamp bounds the final value
+// to Min and Max. A zero Duration never expires, Realtime durations are in
+// seconds and tick with StatsSystem.Update, others are in turns.
+type Modifier struct {
+	ID        string
+	Stat      string
+	Type      ModifierType
+	Value     float64
+	Min       float64
+	Max       float64
+	Source    string
+	Duration  float64
+	Remaining float64
+	Realtime  bool
+	Stacking  StackRule
+	MaxStacks int
+}
+
+func (m *Modifier) key() string {
+	if m.ID != "" {
+		return m.ID
+	}
+
+	return m.Source + ":" + m.Stat
+}
+
+type Stats struct {
+	Base      map[string]float64
+	Pools     map[string]float64
+	Modifiers []*Modifier
+}
+
+func NewStats(base map[string]float64) *Stats {
+	s := &Stats{
+		Base:      make(map[string]float64),
+		Pools:     make(map[string]float64),
+		Modifiers: make([]*Modifier, 0),
+	}
+	for stat, v := range base {
+		s.Base[stat] = v
+	}
+	s.Restore()
+
+	return s
+}
+
+// Get returns the final value of stat: (base + flat) * (1 + percent / 100),
+// replaced by the latest override then clamped.
+func (s *Stats) Get(stat string) float64 {
+	value := s.Base[stat]
+	percent := 0.0
+	override := false
+	overrideValue := 0.0
+	min, max := math.Inf(-1), math.Inf(1)
+
+	for _, m := range s.Modifiers {
+		if m.Stat != stat {
+			continue
+		}
+
+		switch m.Type {
+		case ModifierFlat:
+			value += m.Value
+		case ModifierPercent:
+			percent += m.Value
+		case ModifierOverride:
+			override = true
+			overrideValue = m.Value
+		case ModifierClamp:
+			min = math.Max(min, m.Min)
+			max = math.Min(max, m.Max)
+		}
+	}
+
+	value *= 1 + percent/100
+	if override {
+		value = overrideValue
+	}
+
+	return math.Max(min, math.Min(max, value))
+}
+
+func (s *Stats) Names() []string {
+	seen := make(map[string]bool)
+	names := make([]string, 0)
+	for stat := range s.Base {
+		seen[stat] = true
+		names = append(names, stat)
+	}
+	for _, m := range s.Modifiers {
+		if !seen[m.Stat] {
+			seen[m.Stat] = true
+			names = append(names, m.Stat)
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func (s *Stats) Values() map[string]float64 {
+	values := make(map[string]float64)
+	for _, stat := range s.Names() {
+		values[stat] = s.Get(stat)
+	}
+
+	return values
+}
+
+func (s *Stats) IsPool(stat string) bool {
+	return isPool(stat)
+}
+
+func (s *Stats) Pool(stat string) float64 {
+	return s.Pools[stat]
+}
+
+// SetPool sets the current value of a pool stat within 0 and its final value.
+func (s *Stats) SetPool(stat string, value float64) {
+	s.Pools[stat] = math.Max(0, math.Min(s.Get(stat), value))
+}
+
+// AddPool changes a pool stat by delta and returns the change actually applied.
+func (s *Stats) AddPool(stat string, delta float64) float64 {
+	old := s.Pools[stat]
+	s.SetPool(stat, old+delta)
+
+	return s.Pools[stat] - old
+}
+
+// Restore fills every pool stat.
+func (s *Stats) Restore() {
+	for _, p := range PoolStats {
+		if _, ok := s.Base[p]; ok {
+			s.Pools[p] = s.Get(p)
+		}
+	}
+}
+
+// ClampPools keeps pools within their maximum after a change.
+func (s *Stats) ClampPools() {
+	for stat, v := range s.Pools {
+		s.SetPool(stat, v)
+	}
+}
+
+// AddModifier applies m following its stacking rule and returns false when the
+// rule discarded it.
+func (s *Stats) AddModifier(m *Modifier) bool {
+	m.Remaining = m.Duration
+
+	same := make([]*Modifier, 0)
+	for _, o := range s.Modifiers {
+		if o.key() == m.key() {
+			same = append(same, o)
+		}
+	}
+
+	switch m.Stacking {
+	case StackRefresh:
+		for _, o := range same {
+			s.RemoveModifier(o)
+		}
+	case StackHighest:
+		for _, o := range same {
+			if math.Abs(o.Value) > math.Abs(m.Value) {
+				o.Remaining = math.Max(o.Remaining, m.Duration)
+				return false
+			}
+			s.RemoveModifier(o)
+		}
+	case StackLimit:
+		if m.MaxStacks > 0 && len(same) >= m.MaxStacks {
+			for _, o := range same {
+				o.Remaining = o.Duration
+			}
+			return false
+		}
+	}
+
+	s.Modifiers = append(s.Modifiers, m)
+	s.ClampPools()
+
+	return true
+}
+
+func (s *Stats) RemoveModifier(m *Modifier) bool {
+	for i, o := range s.Modifiers {
+		if o == m {
+			s.Modifiers = append(s.Modifiers[:i], s.Modifiers[i+1:]...)
+			s.ClampPools()
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Stats) RemoveSource(source string) []*Modifier {
+	removed := make([]*Modifier, 0)
+	kept := make([]*Modifier, 0, len(s.Modifiers))
+	for _, m := range s.Modifiers {
+		if m.Source == source {
+			removed = append(removed, m)
+		} else {
+			kept = append(kept, m)
+		}
+	}
+	s.Modifiers = kept
+	s.ClampPools()
+
+	return removed
+}
+
+// Tick advances the duration of turn based or realtime modifiers and returns
+// the expired ones, which are removed.
+func (s *Stats) Tick(elapsed float64, realtime bool) []*Modifier {
+	expired := make([]*Modifier, 0)
+	kept := make([]*Modifier, 0, len(s.Modifiers))
+	for _, m := range s.Modifiers {
+		if m.Duration > 0 && m.Realtime == realtime {
+			m.Remaining -= elapsed
+			if m.Remaining <= 0 {
+				expired = append(expired, m)
+				continue
+			}
+		}
+		kept = append(kept, m)
+	}
+	s.Modifiers = kept
+	if len(expired) > 0 {
+		s.ClampPools()
+	}
+
+	return expired
+}
+
+func (s *Stats) Copy() *Stats {
+	cpy := NewStats(s.Base)
+	for stat, v := range s.Pools {
+		cpy.Pools[stat] = v
+	}
+	for _, m := range s.Modifiers {
+		mc := *m
+		cpy.Modifiers = append(cpy.Modifiers, &mc)
+	}
+
+	return cpy
+}
+
+func isPool(stat string) bool {
+	for _, p := range PoolStats {
+		if p == stat {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isStat(stat string) bool {
+	for _, n := range StatNames {
+		if n == stat {
+			return true
+		}
+	}
+
+	return false
+}
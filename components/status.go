@@ -0,0 +1,146 @@
+package components
+
+import "image/color"
+
+const (
+	StatusPoisoned  = "poisoned"
+	StatusSleep     = "sleep"
+	StatusParalyzed = "paralyzed"
+	StatusConfused  = "confused"
+	StatusCharmed   = "charmed"
+	StatusStoned    = "stoned"
+	StatusDead      = "dead"
+)
+
+// StatusPriority lists every known status from the most to the least visible,
+// the first active one tints the entity.
+var StatusPriority = []string{
+	StatusDead,
+	StatusStoned,
+	StatusParalyzed,
+	StatusSleep,
+	StatusCharmed,
+	StatusConfused,
+	StatusPoisoned,
+}
+
+var StatusColors = map[string]color.Color{
+	StatusPoisoned:  ColorPoisoned,
+	StatusSleep:     ColorSleep,
+	StatusParalyzed: ColorParalyzed,
+	StatusConfused:  ColorConfused,
+	StatusCharmed:   ColorCharmed,
+	StatusStoned:    ColorStoned,
+	StatusDead:      ColorDead,
+}
+
+func isStatus(name string) bool {
+	for _, n := range StatusPriority {
+		if n == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// StatusEffect is a timed condition. Its effect runs once per turn, or every
+// Interval seconds when Realtime: Damage and DamagePercent (of max HP) hurt,
+// negative values heal, and Element scales the damage by the resistances of
+// the entity. A zero Duration lasts until cured.
+type StatusEffect struct {
+	Name          string
+	Source        string
+	Element       string
+	Duration      float64
+	Remaining     float64
+	Realtime      bool
+	Interval      float64
+	Damage        float64
+	DamagePercent float64
+	SkipTurn      bool
+	BreakOnDamage bool
+	elapsed       float64
+}
+
+// NewStatusEffect returns the default effect for one of the Status constants.
+func NewStatusEffect(name string, duration float64) *StatusEffect {
+	se := &StatusEffect{
+		Name:     name,
+		Duration: duration,
+		Interval: 1,
+	}
+
+	switch name {
+	case StatusPoisoned:
+		se.DamagePercent = 10
+		se.Element = ElementPoison
+	case StatusSleep:
+		se.SkipTurn = true
+		se.BreakOnDamage = true
+	case StatusParalyzed, StatusStoned, StatusDead:
+		se.SkipTurn = true
+	}
+
+	return se
+}
+
+// Advance moves the effect forward by elapsed turns or seconds and returns how
+// many times its effect triggers.
+func (se *StatusEffect) Advance(elapsed float64) int {
+	triggers := 1
+	if se.Realtime {
+		interval := se.Interval
+		if interval <= 0 {
+			interval = 1
+		}
+		se.elapsed += elapsed
+		triggers = int(se.elapsed / interval)
+		se.elapsed -= float64(triggers) * interval
+	}
+
+	if se.Duration > 0 {
+		se.Remaining -= elapsed
+	}
+
+	return triggers
+}
+
+func (se *StatusEffect) Expired() bool {
+	return se.Duration > 0 && se.Remaining <= 0
+}
+
+func (ge *GameEntity) GetStatus(name string) *StatusEffect {
+	for _, s := range ge.Statuses {
+		if s.Name == name {
+			return s
+		}
+	}
+
+	return nil
+}
+
+func (ge *GameEntity) HasStatus(name string) bool {
+	return ge.GetStatus(name) != nil
+}
+
+func (ge *GameEntity) CanAct() bool {
+	for _, s := range ge.Statuses {
+		if s.SkipTurn {
+			return false
+		}
+	}
+
+	return true
+}
+
+// StatusColor returns the tint of the most visible active status, or nil.
+func (ge *GameEntity) StatusColor() color.Color {
+	for _, name := range StatusPriority {
+		if ge.HasStatus(name) {
+			return StatusColors[name]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,242 @@
+package components
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"path/filepath"
+	"sort"
+)
+
+const TableMaxDepth = 16
+
+var TablesDir = filepath.Join(DataRoot, "tables")
+
+// TableEntry is one weighted line of a RandomTable. It yields Result, Dice
+// times (once when empty), rolls the nested Table, or with Reroll rolls its own
+// table that many more times, ignoring other Reroll entries.
+type TableEntry struct {
+	Weight float64 `json:"weight"`
+	Result string  `json:"result"`
+	Dice   string  `json:"dice"`
+	Table  string  `json:"table"`
+	Reroll int     `json:"reroll"`
+	dice   *DiceExpression
+}
+
+type RandomTable struct {
+	Name    string       `json:"name"`
+	Entries []TableEntry `json:"entries"`
+}
+
+type TableResult struct {
+	Table  string
+	Result string
+	Count  int
+}
+
+func (tr TableResult) String() string {
+	if tr.Count == 1 {
+		return tr.Result
+	}
+
+	return fmt.Sprintf("%d %s", tr.Count, tr.Result)
+}
+
+type RandomTables struct {
+	tables map[string]*RandomTable
+}
+
+func NewRandomTables() *RandomTables {
+	return &RandomTables{
+		tables: make(map[string]*RandomTable),
+	}
+}
+
+func (rt *RandomTables) Add(table *RandomTable) error {
+	if table.Name == "" {
+		return fmt.Errorf("Tables:Add - table has no name")
+	}
+	if rt.tables[table.Name] != nil {
+		return fmt.Errorf("Tables:Add - table %s already exists", table.Name)
+	}
+	if len(table.Entries) == 0 {
+		return fmt.Errorf("Tables:Add - table %s has no entries", table.Name)
+	}
+
+	total := 0.0
+	for i := range table.Entries {
+		e := &table.Entries[i]
+		total += e.Weight
+		if e.Weight < 0 {
+			return fmt.Errorf("Tables:Add - table %s entry %d has a negative weight", table.Name, i)
+		}
+		if e.Reroll < 0 {
+			return fmt.Errorf("Tables:Add - table %s entry %d has a negative reroll", table.Name, i)
+		}
+		if e.Result == "" && e.Table == "" && e.Reroll == 0 {
+			return fmt.Errorf("Tables:Add - table %s entry %d has no result, table or reroll", table.Name, i)
+		}
+		if e.Dice != "" {
+			dice, err := ParseDices(e.Dice)
+			if err != nil {
+				return fmt.Errorf("Tables:Add - table %s entry %d: %s", table.Name, i, err)
+			}
+			e.dice = dice
+		}
+	}
+
+	if total <= 0 {
+		return fmt.Errorf("Tables:Add - table %s has no weight", table.Name)
+	}
+
+	rt.tables[table.Name] = table
+
+	return nil
+}
+
+func (rt *RandomTables) Get(name string) *RandomTable {
+	return rt.tables[name]
+}
+
+func (rt *RandomTables) Names() []string {
+	names := make([]string, 0, len(rt.tables))
+	for name := range rt.tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Load reads a JSON array of tables.
+func (rt *RandomTables) Load(data []byte) error {
+	var tables []*RandomTable
+	if err := json.Unmarshal(data, &tables); err != nil {
+		return fmt.Errorf("Tables:Load - %s", err)
+	}
+
+	for _, t := range tables {
+		if err := rt.Add(t); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (rt *RandomTables) LoadDir(dir string) error {
+	err := LoadDataDir(dir, func(file string, data []byte) error {
+		return rt.Load(data)
+	})
+	if err != nil {
+		return err
+	}
+
+	return rt.Validate()
+}
+
+// Validate checks that every nested table exists.
+func (rt *RandomTables) Validate() error {
+	for _, name := range rt.Names() {
+		for i, e := range rt.tables[name].Entries {
+			if e.Table != "" && rt.tables[e.Table] == nil {
+				return fmt.Errorf("Tables:Validate - table %s entry %d references unknown table %s", name, i, e.Table)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (rt *RandomTables) Roll(name string) ([]TableResult, error) {
+	return rt.RollWith(name, GameRand)
+}
+
+func (rt *RandomTables) RollWith(name string, rnd *rand.Rand) ([]TableResult, error) {
+	results := make([]TableResult, 0)
+	err := rt.roll(name, rnd, 0, &results)
+
+	return results, err
+}
+
+func (rt *RandomTables) roll(name string, rnd *rand.Rand, depth int, results *[]TableResult) error {
+	if depth > TableMaxDepth {
+		return fmt.Errorf("Tables:Roll - table %s nested too deep", name)
+	}
+
+	table := rt.tables[name]
+	if table == nil {
+		return fmt.Errorf("Tables:Roll - unknown table %s", name)
+	}
+
+	entry := table.pick(rnd, false)
+	if entry.Reroll > 0 {
+		for i := 0; i < entry.Reroll; i++ {
+			if err := rt.resolve(table, table.pick(rnd, true), rnd, depth, results); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return rt.resolve(table, entry, rnd, depth, results)
+}
+
+func (rt *RandomTables) resolve(table *RandomTable, entry *TableEntry, rnd *rand.Rand, depth int, results *[]TableResult) error {
+	if entry == nil {
+		return fmt.Errorf("Tables:Roll - table %s has only reroll entries", table.Name)
+	}
+
+	count := 1
+	if entry.dice != nil {
+		count = entry.dice.roll(rnd).Total
+	}
+
+	if entry.Table != "" {
+		for i := 0; i < count; i++ {
+			if err := rt.roll(entry.Table, rnd, depth+1, results); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if count > 0 {
+		*results = append(*results, TableResult{
+			Table:  table.Name,
+			Result: entry.Result,
+			Count:  count,
+		})
+	}
+
+	return nil
+}
+
+func (t *RandomTable) pick(rnd *rand.Rand, skipReroll bool) *TableEntry {
+	total := 0.0
+	for _, e := range t.Entries {
+		if !skipReroll || e.Reroll == 0 {
+			total += e.Weight
+		}
+	}
+	if total <= 0 {
+		return nil
+	}
+
+	r := rnd.Float64() * total
+	var last *TableEntry
+	for i := range t.Entries {
+		e := &t.Entries[i]
+		if skipReroll && e.Reroll > 0 || e.Weight == 0 {
+			continue
+		}
+		last = e
+		r -= e.Weight
+		if r < 0 {
+			return e
+		}
+	}
+
+	return last
+}
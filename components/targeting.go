@@ -0,0 +1,182 @@
+package components
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+const (
+	TargetEnemy      = "enemy"
+	TargetAlly       = "ally"
+	TargetSelf       = "self"
+	TargetAllEnemies = "all-enemies"
+	TargetAllAllies  = "all-allies"
+	TargetRandom     = "random"
+	TargetRow        = "row"
+	TargetColumn     = "column"
+)
+
+var DefaultTargeting = Targeting{Mode: TargetEnemy}
+
+// Targeting is how an action picks its targets, Count is the number of random
+// enemies hit by TargetRandom.
+type Targeting struct {
+	Mode  string `json:"mode"`
+	Count int    `json:"count"`
+}
+
+// Slot is the place of an entity in the formation of its side.
+type Slot struct {
+	Row    int
+	Column int
+}
+
+// Selection is an ongoing choice of targets, Index is the focused candidate.
+type Selection struct {
+	Battle     *Battle
+	Actor      *GameEntity
+	Targeting  Targeting
+	Candidates Targets
+	Index      int
+}
+
+func (t Targeting) Validate() error {
+	switch t.Mode {
+	case TargetEnemy, TargetAlly, TargetSelf, TargetAllEnemies, TargetAllAllies, TargetRow, TargetColumn:
+		return nil
+	case TargetRandom:
+		if t.Count < 1 {
+			return fmt.Errorf("Targeting:Validate - Random targeting needs a count, got %d", t.Count)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("Targeting:Validate - Unknown target mode %s", t.Mode)
+}
+
+func (t Targeting) String() string {
+	if t.Mode == TargetRandom {
+		return fmt.Sprintf("%s %d", t.Mode, t.Count)
+	}
+
+	return t.Mode
+}
+
+// SlotOf returns the formation slot of ge, by default entities stand on the
+// first row, one column each.
+func (s *Side) SlotOf(ge *GameEntity) Slot {
+	if slot, ok := s.Formation[ge]; ok {
+		return slot
+	}
+
+	for i, e := range s.Entities {
+		if e == ge {
+			return Slot{Column: i}
+		}
+	}
+
+	return Slot{}
+}
+
+// Candidates returns the living entities actor can focus with mode.
+func (b *Battle) Candidates(actor *GameEntity, mode string) Targets {
+	switch mode {
+	case TargetSelf:
+		return Targets{actor}
+	case TargetAlly, TargetAllAllies:
+		return b.Allies(actor)
+	}
+
+	return b.Enemies(actor)
+}
+
+// Affected returns every entity hit when focusing focus, random targeting
+// returns all the entities that may be picked.
+func (b *Battle) Affected(actor *GameEntity, targeting Targeting, focus *GameEntity) Targets {
+	candidates := b.Candidates(actor, targeting.Mode)
+
+	switch targeting.Mode {
+	case TargetEnemy, TargetAlly:
+		if focus == nil {
+			return Targets{}
+		}
+		return Targets{focus}
+	case TargetRow, TargetColumn:
+		side := b.SideOf(focus)
+		if side == nil {
+			return Targets{}
+		}
+
+		slot := side.SlotOf(focus)
+		affected := make(Targets, 0)
+		for _, c := range candidates {
+			if b.SideOf(c) != side {
+				continue
+			}
+
+			s := side.SlotOf(c)
+			if targeting.Mode == TargetRow && s.Row == slot.Row || targeting.Mode == TargetColumn && s.Column == slot.Column {
+				affected = append(affected, c)
+			}
+		}
+		return affected
+	}
+
+	return candidates
+}
+
+// ResolveTargets returns the final targets, random targeting picks Count
+// distinct entities, or all of them when there are fewer.
+func (b *Battle) ResolveTargets(actor *GameEntity, targeting Targeting, focus *GameEntity, rnd *rand.Rand) Targets {
+	affected := b.Affected(actor, targeting, focus)
+	if targeting.Mode != TargetRandom {
+		return affected
+	}
+
+	if rnd == nil {
+		rnd = GameRand
+	}
+
+	picked := append(Targets(nil), affected...)
+	rnd.Shuffle(len(picked), func(i, j int) {
+		picked[i], picked[j] = picked[j], picked[i]
+	})
+	if targeting.Count < len(picked) {
+		picked = picked[:targeting.Count]
+	}
+
+	return picked
+}
+
+func NewSelection(battle *Battle, actor *GameEntity, targeting Targeting) *Selection {
+	return &Selection{
+		Battle:     battle,
+		Actor:      actor,
+		Targeting:  targeting,
+		Candidates: battle.Candidates(actor, targeting.Mode),
+	}
+}
+
+func (s *Selection) Focus() *GameEntity {
+	if len(s.Candidates) == 0 {
+		return nil
+	}
+
+	return s.Candidates[s.Index]
+}
+
+func (s *Selection) Next() {
+	if len(s.Candidates) > 0 {
+		s.Index = (s.Index + 1) % len(s.Candidates)
+	}
+}
+
+func (s *Selection) Prev() {
+	if len(s.Candidates) > 0 {
+		s.Index = (s.Index + len(s.Candidates) - 1) % len(s.Candidates)
+	}
+}
+
+func (s *Selection) Affected() Targets {
+	return s.Battle.Affected(s.Actor, s.Targeting, s.Focus())
+}
@@ -9,3 +9,10 @@ const (
 	LayerFrontBackground = 5
 	LayerFront           = 6
 )
+
+// Bar is a gauge drawn as a Fill rectangle scaled over its Back rectangle.
+type Bar struct {
+	Back  *Entity
+	Fill  *Entity
+	Ratio float32
+}
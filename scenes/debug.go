@@ -6,6 +6,7 @@ import (
 	"github.com/EngoEngine/engo"
 	"github.com/EngoEngine/engo/common"
 	"image/color"
+	"log"
 	"tools/components"
 	"tools/systems"
 )
@@ -21,10 +22,25 @@ const (
 type DebugScene struct {
 	world *ecs.World
 	es    systems.EventSystem
+	sm    systems.FsmSystem
 	em    systems.EntityManager
 	ds    systems.DragSystem
 	ui    systems.UiSystem
 	ms    systems.MenuSystem
+	dk    systems.DeckSystem
+	st    systems.StatsSystem
+	ss    systems.StatusSystem
+	ab    systems.AbilitySystem
+	pf    systems.PrefabSystem
+	iv    systems.InventorySystem
+	el    systems.ElementSystem
+	lv    systems.LevelSystem
+	ai    systems.AiSystem
+	bt    systems.BtSystem
+	ts    systems.TargetSystem
+	cs    systems.CombatSystem
+	fs    systems.FloatingTextSystem
+	fl    systems.FlowSystem
 }
 
 // Preload initializes assets
@@ -43,10 +59,25 @@ func (ds *DebugScene) Setup(u engo.Updater) {
 
 	ds.world = u.(*ecs.World)
 	ds.world.AddSystem(&ds.es)
+	ds.world.AddSystem(&ds.sm)
 	ds.world.AddSystem(&ds.em)
 	ds.world.AddSystem(&ds.ds)
 	ds.world.AddSystem(&ds.ui)
 	ds.world.AddSystem(&ds.ms)
+	ds.world.AddSystem(&ds.dk)
+	ds.world.AddSystem(&ds.st)
+	ds.world.AddSystem(&ds.ss)
+	ds.world.AddSystem(&ds.ab)
+	ds.world.AddSystem(&ds.pf)
+	ds.world.AddSystem(&ds.iv)
+	ds.world.AddSystem(&ds.el)
+	ds.world.AddSystem(&ds.lv)
+	ds.world.AddSystem(&ds.ts)
+	ds.world.AddSystem(&ds.cs)
+	ds.world.AddSystem(&ds.fs)
+	ds.world.AddSystem(&ds.ai)
+	ds.world.AddSystem(&ds.bt)
+	ds.world.AddSystem(&ds.fl)
 
 	// Listen the EventMenuItemClicked event
 	ds.es.Listen(systems.EventMenuItemClicked, func(m engo.Message) {
@@ -73,22 +104,32 @@ func (ds *DebugScene) Setup(u engo.Updater) {
 
 func (ds *DebugScene) setupKeys() {
 	mapping := map[string]engo.Key{
-		"Q":   engo.KeyQ,
-		"E":   engo.KeyE,
-		"W":   engo.KeyW,
-		"TAB": engo.KeyTab,
-		"F1":  engo.KeyF1,
-		"F2":  engo.KeyF2,
-		"F3":  engo.KeyF3,
-		"F4":  engo.KeyF4,
-		"F5":  engo.KeyF5,
-		"F6":  engo.KeyF6,
-		"F7":  engo.KeyF7,
-		"F8":  engo.KeyF8,
-		"F9":  engo.KeyF9,
-		"F10": engo.KeyF10,
-		"F11": engo.KeyF11,
-		"F12": engo.KeyF12,
+		"Q":      engo.KeyQ,
+		"E":      engo.KeyE,
+		"W":      engo.KeyW,
+		"TAB":    engo.KeyTab,
+		"F1":     engo.KeyF1,
+		"F2":     engo.KeyF2,
+		"F3":     engo.KeyF3,
+		"F4":     engo.KeyF4,
+		"F5":     engo.KeyF5,
+		"F6":     engo.KeyF6,
+		"F7":     engo.KeyF7,
+		"F8":     engo.KeyF8,
+		"F9":     engo.KeyF9,
+		"F10":    engo.KeyF10,
+		"F11":    engo.KeyF11,
+		"F12":    engo.KeyF12,
+		"1":      engo.KeyOne,
+		"2":      engo.KeyTwo,
+		"3":      engo.KeyThree,
+		"4":      engo.KeyFour,
+		"5":      engo.KeyFive,
+		"6":      engo.KeySix,
+		"LEFT":   engo.KeyArrowLeft,
+		"RIGHT":  engo.KeyArrowRight,
+		"ENTER":  engo.KeyEnter,
+		"ESCAPE": engo.KeyEscape,
 	}
 
 	input := engo.Input
@@ -105,6 +146,22 @@ func (ds *DebugScene) Type() string {
 func (ds *DebugScene) tests() {
 	ds.TestMenuComponent()
 	ds.TestMenuRefreshItems()
+	ds.TestRandomTables()
+	ds.TestDeck()
+	ds.TestStats()
+	ds.TestStatus()
+	ds.TestAbilities()
+	ds.TestPrefabs()
+	ds.TestItems()
+	ds.TestEquipment()
+	ds.TestElements()
+	ds.TestLeveling()
+	ds.TestTargeting()
+	ds.TestAi()
+	ds.TestBehaviours()
+	ds.TestFlow()
+	ds.TestCombat()
+	ds.TestAtb()
 }
 
 func (ds *DebugScene) TestMenuComponent() {
@@ -158,3 +215,425 @@ func (ds *DebugScene) TestMenuRefreshItems() {
 	ds.ms.SetItems(menu, newItems)
 	menu.Container.RenderComponent.SetZIndex(components.LayerUi)
 }
+
+func (ds *DebugScene) TestRandomTables() {
+	tables := components.NewRandomTables()
+	err := tables.LoadDir(components.TablesDir)
+	if err != nil {
+		log.Fatalf("DebugScene:TestRandomTables - %s\n", err)
+	}
+
+	loot := components.GameStreams.Get(components.StreamLoot)
+	for _, name := range tables.Names() {
+		results, err := tables.RollWith(name, loot.Rand)
+		if err != nil {
+			log.Fatalf("DebugScene:TestRandomTables - %s\n", err)
+		}
+
+		fmt.Printf("Table %s rolled %v\n", name, results)
+	}
+}
+
+func (ds *DebugScene) TestDeck() {
+	cards := make([]*components.Card, 0)
+	for _, name := range []string{"Strike", "Strike", "Strike", "Defend", "Defend", "Bash", "Fireball"} {
+		cards = append(cards, &components.Card{Name: name})
+	}
+
+	deck := ds.dk.NewDeck("test-deck", cards, components.GameStreams.Get(components.StreamCombat).Rand)
+	hand := ds.dk.Draw(deck, 5)
+	ds.dk.Discard(deck, hand[0])
+	ds.dk.Move(deck, hand[1], components.PileHand, components.PileExhaust)
+	ds.dk.DiscardHand(deck)
+	ds.dk.Draw(deck, 5)
+
+	fmt.Printf("Deck %s hand %v, draw %d, discard %d, exhaust %d\n", deck.Name, deck.Pile(components.PileHand),
+		deck.Count(components.PileDraw), deck.Count(components.PileDiscard), deck.Count(components.PileExhaust))
+}
+
+func (ds *DebugScene) TestStats() {
+	goblin := &components.GameEntity{
+		Name: "Goblin",
+		Type: "monster",
+		Stats: components.NewStats(map[string]float64{
+			components.StatHp:  30,
+			components.StatAtk: 8,
+			components.StatDef: 4,
+			components.StatSpd: 6,
+		}),
+	}
+	ds.st.Add(goblin)
+
+	ds.st.AddModifier(goblin, &components.Modifier{
+		Stat:     components.StatAtk,
+		Type:     components.ModifierPercent,
+		Value:    50,
+		Source:   "rage",
+		Duration: 3,
+		Stacking: components.StackRefresh,
+	})
+	ds.st.AddModifier(goblin, &components.Modifier{
+		Stat:     components.StatHp,
+		Type:     components.ModifierFlat,
+		Value:    -10,
+		Source:   "curse",
+		Realtime: true,
+		Duration: 5,
+	})
+	ds.st.AddPool(goblin, components.StatHp, -7)
+
+	fmt.Printf("Goblin stats %v, HP %g\n", goblin.Stats.Values(), goblin.Stats.Pool(components.StatHp))
+}
+
+func (ds *DebugScene) TestStatus() {
+	slime := &components.GameEntity{
+		Name: "Slime",
+		Type: "monster",
+		Stats: components.NewStats(map[string]float64{
+			components.StatHp: 20,
+		}),
+	}
+	ds.st.Add(slime)
+	ds.ss.Add(slime)
+
+	ds.ss.Apply(slime, components.NewStatusEffect(components.StatusPoisoned, 3))
+	ds.ss.Apply(slime, components.NewStatusEffect(components.StatusSleep, 2))
+	for turn := 1; turn <= 4; turn++ {
+		canAct := ds.ss.Turn(slime)
+		fmt.Printf("Slime turn %d: HP %g, can act %t\n", turn, slime.Stats.Pool(components.StatHp), canAct)
+	}
+}
+
+func (ds *DebugScene) TestCombat() {
+	hero := ds.instantiate("hero", "Hero", 100, 300)
+	hero.Inventory = components.NewInventory(8)
+	ds.give(hero, "Potion", 3)
+	ds.give(hero, "Bomb", 1)
+	ds.give(hero, "Bronze Sword", 1)
+	if err := ds.iv.Equip(hero, "Bronze Sword"); err != nil {
+		log.Fatalf("DebugScene:TestCombat - %s\n", err)
+	}
+	goblins := components.Targets{
+		ds.instantiate("goblin", "Goblin", engo.WindowWidth()-200, 200),
+		ds.instantiate("goblin-shaman", "Shaman", engo.WindowWidth()-200, 320),
+		ds.instantiate("goblin-king", "King", engo.WindowWidth()-320, 260),
+	}
+
+	ds.es.Listen(systems.EventActionResolved, func(m engo.Message) {
+		evt := m.(*components.Event)
+		action := evt.Data["action"].(*components.Action)
+
+		for _, r := range action.Results {
+			fmt.Printf("%s %s %s: damage %g, miss %t, critical %t, %s %s\n", action.Actor.Name, action.Name, r.Target.Name, r.Damage, r.Miss, r.Critical, r.Element, r.Affinity)
+		}
+	})
+	ds.es.Listen(systems.EventCombatEnded, func(m engo.Message) {
+		evt := m.(*components.Event)
+		fmt.Printf("Combat ended, victory %t\n", evt.Data["victory"].(bool))
+	})
+
+	ds.cs.BindMenu(ds.ms.Get("test-menu"))
+	ds.cs.StartBattle(
+		&components.Side{Name: "heroes", Entities: components.Targets{hero}, Player: true},
+		&components.Side{Name: "goblins", Entities: goblins},
+	)
+}
+
+// TestElements prints the damage multipliers of the elements against some
+// monsters.
+func (ds *DebugScene) TestElements() {
+	err := ds.el.Load(components.ElementsDir)
+	if err != nil {
+		log.Fatalf("DebugScene:TestElements - %s\n", err)
+	}
+
+	for _, prefab := range []string{"goblin", "goblin-king", "slime"} {
+		ge, err := ds.pf.Prefabs.NewGameEntity(prefab)
+		if err != nil {
+			log.Fatalf("DebugScene:TestElements - %s\n", err)
+		}
+		for _, element := range ds.el.Elements.Names() {
+			multiplier := ds.el.Elements.Multiplier(ge, element)
+			fmt.Printf("%s vs %s: x%g %s\n", element, ge.Name, multiplier, components.Affinity(multiplier))
+		}
+	}
+}
+
+// TestFlow drives the game from the title to the field, and between the field
+// and battles as they start and end.
+func (ds *DebugScene) TestFlow() {
+	ds.es.Listen(systems.EventStateChanged, func(m engo.Message) {
+		evt := m.(*components.Event)
+		if evt.Data["fsm"].(*components.Fsm) != ds.fl.State {
+			return
+		}
+		fmt.Printf("Game %s: %s -> %s\n", evt.Data["transition"], evt.Data["from"], evt.Data["to"])
+	})
+
+	ds.fl.Start()
+	if !ds.fl.State.Is(components.FlowField) {
+		log.Fatalf("DebugScene:TestFlow - game is %s instead of %s\n", ds.fl.State.Current, components.FlowField)
+	}
+}
+
+// TestAtb starts an active time battle once the turn based one is over.
+func (ds *DebugScene) TestAtb() {
+	ds.es.Listen(systems.EventCombatEnded, func(m engo.Message) {
+		evt := m.(*components.Event)
+		if evt.Data["battle"].(*components.Battle).Atb() {
+			return
+		}
+
+		hero := ds.instantiate("hero", "Hero", 100, 500)
+		slime := ds.instantiate("slime", "Slime", engo.WindowWidth()-200, 500)
+
+		ds.cs.StartAtbBattle(components.AtbWait,
+			&components.Side{Name: "heroes", Entities: components.Targets{hero}, Player: true},
+			&components.Side{Name: "slimes", Entities: components.Targets{slime}},
+		)
+	})
+}
+
+func (ds *DebugScene) TestPrefabs() {
+	err := ds.pf.Load(components.PrefabsDir)
+	if err != nil {
+		log.Fatalf("DebugScene:TestPrefabs - %s\n", err)
+	}
+
+	shaman, err := ds.pf.Prefabs.Resolve("goblin-shaman")
+	if err != nil {
+		log.Fatalf("DebugScene:TestPrefabs - %s\n", err)
+	}
+	fmt.Printf("Prefab %s stats %v, abilities %v\n", shaman.Name, shaman.Stats, shaman.Abilities)
+}
+
+func (ds *DebugScene) TestAbilities() {
+	err := ds.ab.Load(components.AbilitiesDir)
+	if err != nil {
+		log.Fatalf("DebugScene:TestAbilities - %s\n", err)
+	}
+
+	for _, name := range ds.ab.Abilities.Names() {
+		a := ds.ab.Get(name)
+		if dice := a.DamageDice(); dice != nil {
+			fmt.Printf("Ability %s deals %s, mean %.1f\n", a.Name, dice, dice.Distribution().Mean())
+		}
+	}
+}
+
+func (ds *DebugScene) TestTargeting() {
+	hero := &components.GameEntity{Name: "Hero"}
+	mage := &components.GameEntity{Name: "Mage"}
+	enemies := components.Targets{
+		&components.GameEntity{Name: "Wolf 1"},
+		&components.GameEntity{Name: "Wolf 2"},
+		&components.GameEntity{Name: "Archer"},
+	}
+	battle := components.NewBattle(
+		&components.Side{Name: "heroes", Entities: components.Targets{hero, mage}, Player: true},
+		&components.Side{Name: "wolves", Entities: enemies, Formation: map[*components.GameEntity]components.Slot{
+			enemies[0]: {Row: 0, Column: 0},
+			enemies[1]: {Row: 0, Column: 1},
+			enemies[2]: {Row: 1, Column: 0},
+		}},
+	)
+
+	modes := []components.Targeting{
+		{Mode: components.TargetEnemy},
+		{Mode: components.TargetAlly},
+		{Mode: components.TargetSelf},
+		{Mode: components.TargetAllEnemies},
+		{Mode: components.TargetRandom, Count: 2},
+		{Mode: components.TargetRow},
+		{Mode: components.TargetColumn},
+	}
+	for _, t := range modes {
+		targets := battle.ResolveTargets(hero, t, battle.Candidates(hero, t.Mode)[0], nil)
+		names := make([]string, 0, len(targets))
+		for _, ge := range targets {
+			names = append(names, ge.Name)
+		}
+		fmt.Printf("Targeting %s: %v\n", t, names)
+	}
+}
+
+func (ds *DebugScene) TestItems() {
+	err := ds.iv.Load(components.ItemsDir)
+	if err != nil {
+		log.Fatalf("DebugScene:TestItems - %s\n", err)
+	}
+
+	knight := &components.GameEntity{Name: "Knight", Inventory: components.NewInventory(4)}
+	squire := &components.GameEntity{Name: "Squire"}
+	ds.iv.Share(knight.Inventory, knight, squire)
+
+	ds.give(squire, "Antidote", 25)
+	ds.give(knight, "Bronze Sword", 1)
+	ds.give(knight, "Old Key", 1)
+	ds.give(knight, "Potion", 5)
+	if err := ds.iv.Equip(knight, "Bronze Sword"); err != nil {
+		log.Fatalf("DebugScene:TestItems - %s\n", err)
+	}
+	if _, err := ds.iv.Drop(squire, "Old Key", 1); err != nil {
+		fmt.Printf("DebugScene:TestItems - %s\n", err)
+	}
+
+	fmt.Printf("Party inventory %v, knight weapon %s\n", knight.Inventory.Stacks, knight.Equipment["weapon"].Name)
+
+	// Items used from the field go through the same pipeline as in battle
+	squire.Stats = components.NewStats(map[string]float64{components.StatHp: 30})
+	squire.Stats.AddPool(components.StatHp, -20)
+	if _, err := ds.cs.UseItem(knight, "Potion", squire); err != nil {
+		log.Fatalf("DebugScene:TestItems - %s\n", err)
+	}
+	fmt.Printf("Squire HP %g after a Potion\n", squire.Stats.Pool(components.StatHp))
+}
+
+func (ds *DebugScene) TestEquipment() {
+	knight := &components.GameEntity{
+		Name:      "Knight",
+		Stats:     components.NewStats(map[string]float64{"HP": 30, "ATK": 8, "DEF": 4, "SPD": 5}),
+		Inventory: components.NewInventory(0),
+	}
+	ds.st.Add(knight)
+	defer ds.st.RemoveGameEntity(knight)
+
+	for _, item := range []string{"Bronze Sword", "Iron Sword", "Leather Armor", "Power Ring", "Power Ring"} {
+		ds.give(knight, item, 1)
+	}
+
+	for _, item := range []string{"Bronze Sword", "Iron Sword", "Power Ring"} {
+		deltas, err := ds.iv.Compare(knight, item)
+		if err != nil {
+			log.Fatalf("DebugScene:TestEquipment - %s\n", err)
+		}
+		fmt.Printf("Compare %s: %v\n", item, deltas)
+		if err := ds.iv.Equip(knight, item); err != nil {
+			log.Fatalf("DebugScene:TestEquipment - %s\n", err)
+		}
+	}
+
+	if err := ds.iv.EquipTo(knight, "Leather Armor", "head"); err != nil {
+		fmt.Printf("DebugScene:TestEquipment - %s\n", err)
+	}
+	if err := ds.iv.Equip(knight, "Power Ring"); err != nil {
+		log.Fatalf("DebugScene:TestEquipment - %s\n", err)
+	}
+	if err := ds.iv.Unequip(knight, "weapon"); err != nil {
+		log.Fatalf("DebugScene:TestEquipment - %s\n", err)
+	}
+
+	for slot, item := range knight.Equipment {
+		fmt.Printf("Knight %s: %s\n", slot, item.Name)
+	}
+	fmt.Printf("Knight stats %v, inventory %v\n", knight.Stats.Values(), knight.Inventory.Stacks)
+}
+
+// TestLeveling levels up the winners of the active time battle and shows the
+// summary of their gains.
+func (ds *DebugScene) TestLeveling() {
+	err := ds.lv.Load(components.ProgressionsDir)
+	if err != nil {
+		log.Fatalf("DebugScene:TestLeveling - %s\n", err)
+	}
+
+	shaman, err := ds.pf.Prefabs.NewGameEntity("goblin-shaman")
+	if err != nil {
+		log.Fatalf("DebugScene:TestLeveling - %s\n", err)
+	}
+	if err := ds.lv.SetLevel(shaman, shaman.Level); err != nil {
+		log.Fatalf("DebugScene:TestLeveling - %s\n", err)
+	}
+	fmt.Printf("Shaman level %d, xp %d, next level in %d\n", shaman.Level, shaman.Xp, ds.lv.Next(shaman))
+
+	ds.es.Listen(systems.EventCombatEnded, func(m engo.Message) {
+		evt := m.(*components.Event)
+		if !evt.Data["battle"].(*components.Battle).Atb() || !evt.Data["victory"].(bool) {
+			return
+		}
+
+		gains := make([]*components.LevelGain, 0)
+		for _, ge := range evt.Data["winner"].(*components.Side).Entities {
+			g, err := ds.lv.AddXp(ge, 200)
+			if err != nil {
+				log.Fatalf("DebugScene:TestLeveling - %s\n", err)
+			}
+			gains = append(gains, g...)
+		}
+		ds.lv.Summary(gains)
+	})
+}
+
+func (ds *DebugScene) TestAi() {
+	err := ds.ai.Load(components.AiDir)
+	if err != nil {
+		log.Fatalf("DebugScene:TestAi - %s\n", err)
+	}
+
+	entities := make(components.Targets, 0)
+	for _, prefab := range []string{"hero", "goblin", "goblin-shaman"} {
+		ge, err := ds.pf.Prefabs.NewGameEntity(prefab)
+		if err != nil {
+			log.Fatalf("DebugScene:TestAi - %s\n", err)
+		}
+		entities = append(entities, ge)
+	}
+	hero, goblin, shaman := entities[0], entities[1], entities[2]
+	battle := components.NewBattle(
+		&components.Side{Name: "heroes", Entities: components.Targets{hero}, Player: true},
+		&components.Side{Name: "goblins", Entities: components.Targets{goblin, shaman}},
+	)
+
+	ctx := &components.AiContext{
+		Battle:    battle,
+		Actor:     shaman,
+		Profile:   ds.ai.Profile(shaman),
+		Abilities: ds.ab.Known(shaman),
+		Rand:      components.GameStreams.Get(components.StreamAi).Rand,
+	}
+	for _, hp := range []float64{100, 30} {
+		goblin.Stats.SetPool(components.StatHp, goblin.Stats.Get(components.StatHp)*hp/100)
+		d := components.AiRulesStrategy(ctx)
+		fmt.Printf("Shaman with goblin at %g%% HP: %s on %s\n", hp, d.Ability.Name, d.Focus.Name)
+	}
+}
+
+// TestBehaviours makes a villager patrol in the field, the goblin king of the
+// combat test follows its own tree.
+func (ds *DebugScene) TestBehaviours() {
+	err := ds.bt.Load(components.BehavioursDir)
+	if err != nil {
+		log.Fatalf("DebugScene:TestBehaviours - %s\n", err)
+	}
+
+	villager := ds.instantiate("slime", "Villager", 100, engo.WindowHeight()-100)
+	villager.Behaviour = "villager"
+	if _, err := ds.bt.Attach(villager); err != nil {
+		log.Fatalf("DebugScene:TestBehaviours - %s\n", err)
+	}
+}
+
+func (ds *DebugScene) give(ge *components.GameEntity, item string, count int) {
+	added, err := ds.iv.Give(ge, item, count)
+	if err != nil {
+		log.Fatalf("DebugScene:give - %s\n", err)
+	}
+	if added < count {
+		fmt.Printf("DebugScene:give - only %d/%d %s fit in the inventory of %s\n", added, count, item, ge.Name)
+	}
+}
+
+// instantiate creates a combatant from a prefab, managed by the stats and
+// status systems.
+func (ds *DebugScene) instantiate(prefab string, name string, x, y float32) *components.GameEntity {
+	ge, err := ds.pf.Instantiate(prefab, engo.Point{X: x, Y: y})
+	if err != nil {
+		log.Fatalf("DebugScene:instantiate - %s\n", err)
+	}
+	ge.Name = name
+
+	ds.st.Add(ge)
+	ds.ss.Add(ge)
+
+	return ge
+}
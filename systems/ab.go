@@ -0,0 +1,109 @@
+package systems
+
+import (
+	"fmt"
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+	"tools/components"
+)
+
+type AbilitySystem struct {
+	ms        *MenuSystem
+	st        *StatsSystem
+	Abilities *components.Abilities
+}
+
+func (ab *AbilitySystem) New(w *ecs.World) {
+	ab.Abilities = components.NewAbilities()
+
+	for _, system := range w.Systems() {
+		switch sys := system.(type) {
+		case *MenuSystem:
+			ab.ms = sys
+		case *StatsSystem:
+			ab.st = sys
+		}
+	}
+}
+
+func (ab *AbilitySystem) Update(dt float32) {
+	if engo.Input.Button("F3").JustPressed() {
+		ab.Debug()
+	}
+}
+
+func (ab *AbilitySystem) Remove(e ecs.BasicEntity) {
+}
+
+// Load adds the abilities defined in the JSON files of dir, failing on the
+// first invalid definition.
+func (ab *AbilitySystem) Load(dir string) error {
+	return ab.Abilities.LoadDir(dir)
+}
+
+func (ab *AbilitySystem) Get(name string) *components.Ability {
+	return ab.Abilities.Get(name)
+}
+
+// Known returns the abilities of ge, skipping unknown names.
+func (ab *AbilitySystem) Known(ge *components.GameEntity) []*components.Ability {
+	known := make([]*components.Ability, 0, len(ge.Abilities))
+	for _, name := range ge.Abilities {
+		if a := ab.Abilities.Get(name); a != nil {
+			known = append(known, a)
+		}
+	}
+
+	return known
+}
+
+// SetMenu replaces the items of menu with the abilities of ge, the ones it
+// cannot pay for are disabled.
+func (ab *AbilitySystem) SetMenu(menu *components.Menu, ge *components.GameEntity, extra ...string) {
+	known := ab.Known(ge)
+	items := make([]string, 0, len(known)+len(extra))
+	disabled := make([]int, 0)
+	for i, a := range known {
+		items = append(items, a.Name)
+		if !a.CanPay(ge) {
+			disabled = append(disabled, i)
+		}
+	}
+	items = append(items, extra...)
+
+	ab.ms.Clean(menu)
+	ab.ms.SetItems(menu, items)
+	ab.ms.DisableItems(menu, disabled...)
+}
+
+// Pay removes the cost of the ability from the pools of ge, nothing is paid
+// when it cannot afford all of it.
+func (ab *AbilitySystem) Pay(ge *components.GameEntity, ability *components.Ability) bool {
+	if !ability.CanPay(ge) {
+		return false
+	}
+
+	for stat, cost := range ability.Cost {
+		if cost == 0 {
+			continue
+		}
+		if ab.st != nil {
+			ab.st.AddPool(ge, stat, -cost)
+			continue
+		}
+		ge.Stats.AddPool(stat, -cost)
+	}
+
+	return true
+}
+
+func (ab *AbilitySystem) Debug() {
+	fmt.Printf("*** Ability System DEBUG ***\n")
+	names := ab.Abilities.Names()
+	fmt.Printf("Instances: %d\n", len(names))
+	for _, name := range names {
+		a := ab.Abilities.Get(name)
+		fmt.Printf("\t- %s: target %s, damage %s, cost %v, element %s, statuses %d\n", a.Name, a.Target, a.Damage, a.Cost, a.Element, len(a.Statuses))
+	}
+	fmt.Printf("\n")
+}
@@ -0,0 +1,169 @@
+package systems
+
+import (
+	"fmt"
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+	"math/rand"
+	"sort"
+	"tools/components"
+)
+
+const EventAiDecided = "EventAiDecided"
+
+// AiSystem chooses the actions of the combatants not driven by the menu. An
+// entity uses the profile named by its Ai, or a profile of the strategy of
+// that name, and random without Ai.
+type AiSystem struct {
+	ev         *EventSystem
+	ab         *AbilitySystem
+	cs         *CombatSystem
+	Rand       *rand.Rand
+	Profiles   *components.AiProfiles
+	strategies map[string]components.AiStrategy
+}
+
+func (ai *AiSystem) New(w *ecs.World) {
+	ai.Profiles = components.NewAiProfiles()
+	ai.Rand = components.GameStreams.Get(components.StreamAi).Rand
+	ai.strategies = make(map[string]components.AiStrategy)
+
+	for _, system := range w.Systems() {
+		switch sys := system.(type) {
+		case *EventSystem:
+			ai.ev = sys
+		case *AbilitySystem:
+			ai.ab = sys
+		case *CombatSystem:
+			ai.cs = sys
+		}
+	}
+
+	ai.RegisterStrategy(components.AiRandom, components.AiRandomStrategy)
+	ai.RegisterStrategy(components.AiLowestHp, components.AiLowestHpStrategy)
+	ai.RegisterStrategy(components.AiHealWhenLow, components.AiHealWhenLowStrategy)
+	ai.RegisterStrategy(components.AiRules, components.AiRulesStrategy)
+
+	ai.ev.NewEvent(EventAiDecided)
+
+	if ai.cs != nil {
+		ai.cs.SetChooser(ai.Choose)
+	}
+}
+
+func (ai *AiSystem) Update(dt float32) {
+	if engo.Input.Button("2").JustPressed() {
+		ai.Debug()
+	}
+}
+
+func (ai *AiSystem) Remove(e ecs.BasicEntity) {
+}
+
+func (ai *AiSystem) RegisterStrategy(name string, strategy components.AiStrategy) {
+	ai.strategies[name] = strategy
+}
+
+// Load reads the profiles of dir and checks their strategies and abilities.
+func (ai *AiSystem) Load(dir string) error {
+	if err := ai.Profiles.LoadDir(dir); err != nil {
+		return err
+	}
+
+	for _, name := range ai.Profiles.Names() {
+		p := ai.Profiles.Get(name)
+		if ai.strategies[p.Strategy] == nil {
+			return fmt.Errorf("AI:Load - profile %s has unknown strategy %s", name, p.Strategy)
+		}
+		if ai.ab == nil {
+			continue
+		}
+		for _, r := range p.Rules {
+			if ai.ab.Get(r.Ability) == nil {
+				return fmt.Errorf("AI:Load - profile %s uses unknown ability %s", name, r.Ability)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Profile returns the profile of ge, built from its strategy when no profile
+// has its name.
+func (ai *AiSystem) Profile(ge *components.GameEntity) *components.AiProfile {
+	name := ge.Ai
+	if name == "" {
+		name = components.AiRandom
+	}
+	if p := ai.Profiles.Get(name); p != nil {
+		return p
+	}
+
+	return &components.AiProfile{Name: name, Strategy: name}
+}
+
+// Choose is the ActionChooser of the CombatSystem, it falls back to its
+// default action when the strategy finds nothing to do.
+func (ai *AiSystem) Choose(battle *components.Battle, combatant *components.Combatant) *components.Action {
+	ge := combatant.GameEntity
+	profile := ai.Profile(ge)
+
+	ctx := &components.AiContext{
+		Battle:    battle,
+		Actor:     ge,
+		Profile:   profile,
+		Abilities: make([]*components.Ability, 0),
+		Rand:      ai.Rand,
+	}
+	if ai.ab != nil {
+		for _, a := range ai.ab.Known(ge) {
+			if a.CanPay(ge) {
+				ctx.Abilities = append(ctx.Abilities, a)
+			}
+		}
+	}
+
+	var decision *components.AiDecision
+	if strategy := ai.strategies[profile.Strategy]; strategy != nil {
+		decision = strategy(ctx)
+	} else {
+		fmt.Printf("AI:Choose - Unknown strategy %s for %s\n", profile.Strategy, ge.Name)
+	}
+
+	if decision == nil || decision.Ability == nil {
+		return ai.cs.DefaultAction(battle, combatant)
+	}
+
+	action := ai.cs.NewFocusedAction(ge, decision.Ability.Name, decision.Focus)
+	focus := "nobody"
+	if decision.Focus != nil {
+		focus = decision.Focus.Name
+	}
+	fmt.Printf("AI:Choose - %s (%s) uses %s on %s\n", ge.Name, profile.Name, action.Name, focus)
+
+	ai.ev.Dispatch(EventAiDecided, map[string]any{
+		"gameEntity": ge,
+		"profile":    profile,
+		"action":     action,
+	})
+
+	return action
+}
+
+func (ai *AiSystem) Debug() {
+	fmt.Printf("*** AI System DEBUG ***\n")
+	strategies := make([]string, 0, len(ai.strategies))
+	for name := range ai.strategies {
+		strategies = append(strategies, name)
+	}
+	sort.Strings(strategies)
+	fmt.Printf("Strategies: %v\n", strategies)
+
+	names := ai.Profiles.Names()
+	fmt.Printf("Profiles: %d\n", len(names))
+	for _, name := range names {
+		p := ai.Profiles.Get(name)
+		fmt.Printf("\t- %s: %s, threshold %g, rules %+v\n", name, p.Strategy, p.Threshold, p.Rules)
+	}
+	fmt.Printf("\n")
+}
@@ -0,0 +1,196 @@
+package systems
+
+import (
+	"fmt"
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+	"math"
+	"math/rand"
+	"tools/components"
+)
+
+const EventBehaviourFinished = "EventBehaviourFinished"
+
+// BtSystem runs the behaviour trees of entities. Attached entities, such as
+// field NPCs, tick every frame with Rand, while the behaviour AI strategy
+// ticks the tree of a combatant once per turn with the AI stream. Frame timing
+// thus never changes the draws of AI decisions. Attached entities tick in
+// attach order so a seed always gives them the same draws.
+type BtSystem struct {
+	ev        *EventSystem
+	ai        *AiSystem
+	Rand      *rand.Rand
+	Library   *components.BtLibrary
+	Trees     *components.BehaviourTrees
+	instances map[*components.GameEntity]*components.BtInstance
+	attached  []*components.GameEntity
+}
+
+func (bt *BtSystem) New(w *ecs.World) {
+	bt.Library = components.NewBtLibrary()
+	bt.Trees = components.NewBehaviourTrees(bt.Library)
+	bt.Rand = components.GameStreams.Get(components.StreamBehaviour).Rand
+	bt.instances = make(map[*components.GameEntity]*components.BtInstance)
+	bt.attached = make([]*components.GameEntity, 0)
+
+	for _, system := range w.Systems() {
+		switch sys := system.(type) {
+		case *EventSystem:
+			bt.ev = sys
+		case *AiSystem:
+			bt.ai = sys
+		}
+	}
+
+	bt.Library.RegisterAction("move", bt.move)
+
+	bt.ev.NewEvent(EventBehaviourFinished)
+
+	if bt.ai != nil {
+		bt.ai.RegisterStrategy(components.AiBehaviour, bt.choose)
+	}
+}
+
+func (bt *BtSystem) Update(dt float32) {
+	if engo.Input.Button("3").JustPressed() {
+		bt.Debug()
+	}
+
+	for _, ge := range append([]*components.GameEntity(nil), bt.attached...) {
+		instance := bt.instances[ge]
+		if instance == nil || !instance.Active {
+			continue
+		}
+
+		if status := instance.Tick(float64(dt), bt.Rand); status != components.BtRunning {
+			bt.ev.Dispatch(EventBehaviourFinished, map[string]any{
+				"gameEntity": ge,
+				"tree":       instance.Tree,
+				"status":     status,
+			})
+		}
+	}
+}
+
+func (bt *BtSystem) Remove(e ecs.BasicEntity) {
+	for ge := range bt.instances {
+		if ge.Entity != nil && ge.Entity.ID() == e.ID() {
+			bt.Detach(ge)
+		}
+	}
+}
+
+func (bt *BtSystem) Load(dir string) error {
+	return bt.Trees.LoadDir(dir)
+}
+
+// Attach starts ticking the Behaviour tree of ge every frame.
+func (bt *BtSystem) Attach(ge *components.GameEntity) (*components.BtInstance, error) {
+	instance, err := bt.Instance(ge)
+	if err != nil {
+		return nil, err
+	}
+	if !instance.Active {
+		instance.Active = true
+		bt.attached = append(bt.attached, ge)
+	}
+
+	return instance, nil
+}
+
+func (bt *BtSystem) Detach(ge *components.GameEntity) {
+	delete(bt.instances, ge)
+	for i, a := range bt.attached {
+		if a == ge {
+			bt.attached = append(bt.attached[:i], bt.attached[i+1:]...)
+			return
+		}
+	}
+}
+
+// Instance returns the tree of ge, built from its Behaviour on first use.
+func (bt *BtSystem) Instance(ge *components.GameEntity) (*components.BtInstance, error) {
+	if instance := bt.instances[ge]; instance != nil {
+		return instance, nil
+	}
+	if ge.Behaviour == "" {
+		return nil, fmt.Errorf("BT:Instance - %s has no behaviour", ge.Name)
+	}
+
+	instance, err := bt.Trees.NewInstance(ge.Behaviour, ge)
+	if err != nil {
+		return nil, err
+	}
+	bt.instances[ge] = instance
+
+	return instance, nil
+}
+
+// choose is the behaviour AI strategy, it ticks the tree once with the turn
+// context on the blackboard and returns the decision of its use action. A
+// tree finishing without deciding is ticked again from its start.
+func (bt *BtSystem) choose(ctx *components.AiContext) *components.AiDecision {
+	instance, err := bt.Instance(ctx.Actor)
+	if err != nil {
+		fmt.Printf("%s\n", err)
+		return nil
+	}
+
+	bb := instance.Blackboard
+	bb.Set(components.BtKeyAi, ctx)
+	bb.Delete(components.BtKeyDecision)
+	defer bb.Delete(components.BtKeyAi)
+
+	for i := 0; i < 2; i++ {
+		status := instance.Tick(1, ctx.Rand)
+		if decision, ok := bb.Get(components.BtKeyDecision).(*components.AiDecision); ok {
+			return decision
+		}
+		if status == components.BtRunning {
+			break
+		}
+	}
+
+	return nil
+}
+
+// move walks the sprite of the entity by the x, y offset at speed pixels per
+// second.
+func (bt *BtSystem) move(ctx *components.BtContext, leaf *components.BtLeaf) components.BtStatus {
+	e := ctx.GameEntity.Entity
+	if e == nil {
+		return components.BtFailure
+	}
+
+	target, ok := leaf.State["target"].(engo.Point)
+	if !ok {
+		target = engo.Point{
+			X: e.Position.X + float32(leaf.FloatParam("x", 0)),
+			Y: e.Position.Y + float32(leaf.FloatParam("y", 0)),
+		}
+		leaf.State["target"] = target
+	}
+
+	dx, dy := float64(target.X-e.Position.X), float64(target.Y-e.Position.Y)
+	distance := math.Hypot(dx, dy)
+	step := leaf.FloatParam("speed", 64) * ctx.Dt
+	if distance <= step {
+		e.Position = target
+		return components.BtSuccess
+	}
+
+	e.Position.X += float32(dx / distance * step)
+	e.Position.Y += float32(dy / distance * step)
+
+	return components.BtRunning
+}
+
+func (bt *BtSystem) Debug() {
+	fmt.Printf("*** Behaviour Tree System DEBUG ***\n")
+	fmt.Printf("Trees: %v\n", bt.Trees.Names())
+	fmt.Printf("Instances: %d\n", len(bt.instances))
+	for ge, instance := range bt.instances {
+		fmt.Printf("\t- %s: %s %s, active %t, blackboard %v\n", ge.Name, instance.Tree, instance.Status, instance.Active, instance.Blackboard.Keys())
+	}
+	fmt.Printf("\n")
+}
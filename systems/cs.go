@@ -0,0 +1,965 @@
+package systems
+
+import (
+	"fmt"
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/engo/common"
+	"log"
+	"math"
+	"math/rand"
+	"tools/components"
+)
+
+const (
+	EventCombatStarted  = "EventCombatStarted"
+	EventRoundStarted   = "EventRoundStarted"
+	EventTurnStarted    = "EventTurnStarted"
+	EventTurnEnded      = "EventTurnEnded"
+	EventActionResolved = "EventActionResolved"
+	EventCombatEnded    = "EventCombatEnded"
+
+	CombatTurnDelay = 0.5
+	ItemMenuBack    = "Back"
+
+	AtbBarWidth  = 100
+	AtbBarHeight = 8
+	AtbBarMargin = 10
+)
+
+const (
+	combatIdle      = "idle"
+	combatBeginTurn = "begin-turn"
+	combatSelecting = "selecting"
+	combatTargeting = "targeting"
+	combatConfirm   = "confirm"
+	combatAi        = "ai"
+	combatResolving = "resolving"
+)
+
+// ActionResolver applies an action, filling its Results.
+type ActionResolver func(battle *components.Battle, action *components.Action)
+
+// ActionChooser picks the action of a combatant not driven by the menu.
+type ActionChooser func(battle *components.Battle, combatant *components.Combatant) *components.Action
+
+type CombatSystem struct {
+	ev        *EventSystem
+	st        *StatsSystem
+	ss        *StatusSystem
+	ms        *MenuSystem
+	ui        *UiSystem
+	sm        *FsmSystem
+	ts        *TargetSystem
+	ab        *AbilitySystem
+	iv        *InventorySystem
+	el        *ElementSystem
+	battle    *components.Battle
+	menu      *components.Menu
+	resolvers map[string]ActionResolver
+	targeting map[string]components.Targeting
+	selection *components.Selection
+	pending   string
+	item      *components.Item
+	itemMenu  *components.Menu
+	items     []*components.Item
+	bars      map[*components.Combatant]*components.Bar
+	chooser   ActionChooser
+	phase     *components.Fsm
+	delay     float32
+	Rand      *rand.Rand
+}
+
+func (cs *CombatSystem) New(w *ecs.World) {
+	cs.resolvers = make(map[string]ActionResolver)
+	cs.targeting = make(map[string]components.Targeting)
+	cs.bars = make(map[*components.Combatant]*components.Bar)
+	cs.chooser = cs.DefaultAction
+	cs.Rand = components.GameStreams.Get(components.StreamCombat).Rand
+
+	for _, system := range w.Systems() {
+		switch sys := system.(type) {
+		case *EventSystem:
+			cs.ev = sys
+		case *StatsSystem:
+			cs.st = sys
+		case *StatusSystem:
+			cs.ss = sys
+		case *MenuSystem:
+			cs.ms = sys
+		case *UiSystem:
+			cs.ui = sys
+		case *FsmSystem:
+			cs.sm = sys
+		case *TargetSystem:
+			cs.ts = sys
+		case *AbilitySystem:
+			cs.ab = sys
+		case *InventorySystem:
+			cs.iv = sys
+		case *ElementSystem:
+			cs.el = sys
+		}
+	}
+
+	cs.ev.NewEvent(EventCombatStarted)
+	cs.ev.NewEvent(EventRoundStarted)
+	cs.ev.NewEvent(EventTurnStarted)
+	cs.ev.NewEvent(EventTurnEnded)
+	cs.ev.NewEvent(EventActionResolved)
+	cs.ev.NewEvent(EventCombatEnded)
+
+	cs.phase = cs.newPhase()
+	if cs.sm != nil {
+		cs.sm.Add(cs.phase)
+	}
+
+	cs.ev.Listen(EventMenuItemClicked, func(msg engo.Message) {
+		evt := msg.(*components.Event)
+		menu := evt.Data["menu"].(*components.Menu)
+		index := evt.Data["index"].(int)
+
+		if !cs.phase.Is(combatSelecting) {
+			return
+		}
+
+		switch menu {
+		case cs.menu:
+			cs.choose(cs.ms.ItemText(menu, index))
+		case cs.itemMenu:
+			cs.chooseItem(index)
+		}
+	})
+
+	cs.ev.Listen(EventTargetConfirmed, func(msg engo.Message) {
+		evt := msg.(*components.Event)
+		selection := evt.Data["selection"].(*components.Selection)
+		targets := evt.Data["targets"].(components.Targets)
+
+		if selection != cs.selection || !cs.phase.Is(combatTargeting) {
+			return
+		}
+
+		// The confirmed targets are final, resolving them again would draw
+		// from the combat stream
+		cs.selection = nil
+		cs.fire("confirm")
+		action := &components.Action{
+			Name:    cs.pending,
+			Actor:   selection.Actor,
+			Targets: targets,
+			Data:    make(map[string]any),
+		}
+		if cs.item != nil {
+			action.Name = components.ActionUse
+			action.Data["item"] = cs.item
+		}
+		cs.Act(action)
+	})
+
+	// Canceling the targeting goes back to the menu
+	cs.ev.Listen(EventTargetCanceled, func(msg engo.Message) {
+		evt := msg.(*components.Event)
+		selection := evt.Data["selection"].(*components.Selection)
+
+		if selection != cs.selection || !cs.phase.Is(combatTargeting) {
+			return
+		}
+
+		cs.selection = nil
+		cs.fire("select")
+		if cs.item != nil {
+			cs.openItems(selection.Actor)
+			return
+		}
+		cs.openMenu(selection.Actor)
+	})
+}
+
+func (cs *CombatSystem) Update(dt float32) {
+	if engo.Input.Button("F10").JustPressed() {
+		cs.Debug()
+	}
+
+	if cs.battle == nil || cs.battle.Over {
+		return
+	}
+
+	if cs.battle.Atb() {
+		cs.fill(float64(dt))
+	}
+
+	if cs.delay > 0 {
+		cs.delay -= dt
+		return
+	}
+
+	switch cs.phase.Current {
+	case combatIdle:
+		if cs.battle.Atb() {
+			cs.nextReady()
+		}
+	case combatBeginTurn:
+		cs.beginTurn()
+	case combatAi:
+		cs.Act(cs.chooser(cs.battle, cs.battle.Current()))
+	}
+}
+
+// newPhase builds the turn machine of battles: a turn begins, then the menu
+// of a player combatant goes back and forth with targeting until the targets
+// are confirmed, while other combatants wait for their AI. The chosen action
+// is resolved before getting back to idle.
+func (cs *CombatSystem) newPhase() *components.Fsm {
+	return components.NewFsm("combat", combatIdle).
+		AddState(combatBeginTurn, nil, nil).
+		AddState(combatSelecting, nil, nil).
+		AddState(combatTargeting, nil, nil).
+		AddState(combatConfirm, nil, nil).
+		AddState(combatAi, func(from string, to string) {
+			cs.delay = CombatTurnDelay
+		}, nil).
+		AddState(combatResolving, nil, nil).
+		AddTransition("reset", []string{components.FsmAny}, combatIdle, nil).
+		AddTransition("begin", []string{combatIdle, combatBeginTurn}, combatBeginTurn, nil).
+		AddTransition("player", []string{combatBeginTurn}, combatSelecting, func() bool {
+			return cs.battle.Current().Side.Player && cs.menu != nil
+		}).
+		AddTransition("ai", []string{combatBeginTurn}, combatAi, nil).
+		AddTransition("target", []string{combatSelecting}, combatTargeting, nil).
+		AddTransition("select", []string{combatTargeting, combatConfirm}, combatSelecting, nil).
+		AddTransition("confirm", []string{combatTargeting}, combatConfirm, nil).
+		AddTransition("resolve", []string{combatSelecting, combatConfirm, combatAi}, combatResolving, nil).
+		AddTransition("end", []string{combatBeginTurn, combatAi, combatResolving}, combatIdle, nil)
+}
+
+// fire logs the transitions of the turn machine that are not allowed.
+func (cs *CombatSystem) fire(transition string) {
+	if err := cs.phase.Fire(transition); err != nil {
+		fmt.Printf("CS:fire - %s\n", err)
+	}
+}
+
+// playerTurn tells whether a player combatant is choosing its action.
+func (cs *CombatSystem) playerTurn() bool {
+	return cs.phase.Is(combatSelecting) || cs.phase.Is(combatTargeting) || cs.phase.Is(combatConfirm)
+}
+
+func (cs *CombatSystem) Remove(e ecs.BasicEntity) {
+}
+
+func (cs *CombatSystem) RegisterAction(name string, resolver ActionResolver) {
+	cs.resolvers[name] = resolver
+}
+
+// resolver returns the resolver registered for the action, then the one of
+// abilities, and the built-in attack when no Attack ability is defined.
+func (cs *CombatSystem) resolver(name string) ActionResolver {
+	if resolver := cs.resolvers[name]; resolver != nil {
+		return resolver
+	}
+
+	if cs.ability(name) != nil {
+		return cs.ResolveAbility
+	}
+
+	if name == components.ActionAttack {
+		return cs.ResolveAttack
+	}
+
+	return nil
+}
+
+func (cs *CombatSystem) ability(name string) *components.Ability {
+	if cs.ab == nil {
+		return nil
+	}
+
+	return cs.ab.Get(name)
+}
+
+func (cs *CombatSystem) SetTargeting(name string, targeting components.Targeting) {
+	cs.targeting[name] = targeting
+}
+
+// Targeting returns how the action picks its targets, the one of the ability
+// or a single enemy by default.
+func (cs *CombatSystem) Targeting(name string) components.Targeting {
+	if targeting, ok := cs.targeting[name]; ok {
+		return targeting
+	}
+
+	if a := cs.ability(name); a != nil {
+		return a.Target
+	}
+
+	return components.DefaultTargeting
+}
+
+func (cs *CombatSystem) SetChooser(chooser ActionChooser) {
+	cs.chooser = chooser
+}
+
+// BindMenu makes the items of menu the actions of player sides, the menu is
+// shown during their turns only.
+func (cs *CombatSystem) BindMenu(menu *components.Menu) {
+	cs.menu = menu
+	cs.ms.Hide(menu)
+}
+
+func (cs *CombatSystem) GetBattle() *components.Battle {
+	return cs.battle
+}
+
+// StartBattle rolls initiative for every entity of the sides and begins the
+// first round.
+func (cs *CombatSystem) StartBattle(sides ...*components.Side) *components.Battle {
+	return cs.start("", sides)
+}
+
+// StartAtbBattle begins an active time battle, every combatant acts when its
+// gauge is full. In AtbWait mode gauges pause while the player menu is open,
+// in AtbActive mode they keep filling.
+func (cs *CombatSystem) StartAtbBattle(mode string, sides ...*components.Side) *components.Battle {
+	if mode != components.AtbWait && mode != components.AtbActive {
+		log.Fatalf("CS:StartAtbBattle - Unknown ATB mode %s\n", mode)
+	}
+
+	return cs.start(mode, sides)
+}
+
+func (cs *CombatSystem) start(mode string, sides []*components.Side) *components.Battle {
+	if len(sides) < 2 {
+		log.Fatalf("CS:StartBattle - A battle needs at least 2 sides, got %d\n", len(sides))
+	}
+
+	cs.removeBars()
+	cs.closeItems()
+	cs.fire("reset")
+	if cs.selection != nil {
+		cs.selection = nil
+		cs.ts.Cancel()
+	}
+	cs.delay = 0
+
+	battle := components.NewBattle(sides...)
+	battle.Mode = mode
+	for _, side := range sides {
+		for _, ge := range side.Entities {
+			roll := battle.Initiative.RollDetailedWith(cs.Rand)
+			initiative := roll.Total
+			if ge.Stats != nil {
+				initiative += int(ge.Stats.Get(components.StatSpd))
+			}
+
+			battle.Queue = append(battle.Queue, &components.Combatant{
+				GameEntity: ge,
+				Side:       side,
+				Initiative: initiative,
+				Roll:       roll,
+			})
+		}
+	}
+	battle.SortQueue()
+	cs.battle = battle
+
+	// The initiative roll gives a head start to the gauges
+	if battle.Atb() {
+		for _, c := range battle.Queue {
+			c.Gauge = components.NewGauge(components.AtbRate(c.GameEntity))
+			c.Gauge.Value = math.Min(c.Gauge.Max, float64(c.Initiative))
+			cs.bars[c] = cs.ui.NewBar(common.SpaceComponent{
+				Width:  AtbBarWidth,
+				Height: AtbBarHeight,
+			}, components.ColorGaugeBack, components.ColorGauge)
+		}
+		cs.updateBars()
+	}
+
+	for _, c := range battle.Queue {
+		fmt.Printf("CS:StartBattle - %s (%s) initiative %d: %s\n", c.GameEntity.Name, c.Side.Name, c.Initiative, c.Roll)
+	}
+
+	cs.ev.Dispatch(EventCombatStarted, map[string]any{
+		"battle": battle,
+	})
+	cs.startRound()
+
+	return battle
+}
+
+func (cs *CombatSystem) startRound() {
+	cs.battle.Round++
+	cs.battle.Turn = 0
+
+	cs.ev.Dispatch(EventRoundStarted, map[string]any{
+		"battle": cs.battle,
+		"round":  cs.battle.Round,
+	})
+
+	if !cs.battle.Atb() {
+		cs.fire("begin")
+	}
+}
+
+// fill advances the gauges of living combatants, the full ones join the ready
+// queue and stay full until their turn ends.
+func (cs *CombatSystem) fill(dt float64) {
+	if cs.battle.Mode == components.AtbWait && cs.playerTurn() {
+		return
+	}
+
+	for _, c := range cs.battle.Queue {
+		if !components.IsAlive(c.GameEntity) {
+			c.Gauge.Reset()
+			continue
+		}
+		if c.Gauge.Full() {
+			continue
+		}
+
+		c.Gauge.Rate = components.AtbRate(c.GameEntity)
+		if c.Gauge.Fill(dt) {
+			cs.battle.Ready = append(cs.battle.Ready, c)
+		}
+	}
+
+	cs.updateBars()
+}
+
+func (cs *CombatSystem) nextReady() {
+	for len(cs.battle.Ready) > 0 {
+		c := cs.battle.Ready[0]
+		cs.battle.Ready = cs.battle.Ready[1:]
+		if !components.IsAlive(c.GameEntity) {
+			c.Gauge.Reset()
+			continue
+		}
+
+		for i, q := range cs.battle.Queue {
+			if q == c {
+				cs.battle.Turn = i
+			}
+		}
+		cs.fire("begin")
+		cs.beginTurn()
+		return
+	}
+}
+
+func (cs *CombatSystem) updateBars() {
+	i := 0
+	for _, c := range cs.battle.Queue {
+		bar := cs.bars[c]
+		if bar == nil {
+			continue
+		}
+
+		position := engo.Point{
+			X: AtbBarMargin,
+			Y: AtbBarMargin + float32(i)*(AtbBarHeight+AtbBarMargin),
+		}
+		if e := c.GameEntity.Entity; e != nil {
+			position.X = e.Position.X + (e.Width-AtbBarWidth)/2
+			position.Y = e.Position.Y - AtbBarHeight - AtbBarMargin
+		} else {
+			i++
+		}
+		cs.ui.MoveBar(bar, position)
+
+		clr := components.ColorGauge
+		if c.Gauge.Full() {
+			clr = components.ColorGaugeFull
+		}
+		cs.ui.UpdateBar(bar, float32(c.Gauge.Ratio()), clr)
+	}
+}
+
+func (cs *CombatSystem) removeBars() {
+	for c, bar := range cs.bars {
+		cs.ui.RemoveBar(bar)
+		delete(cs.bars, c)
+	}
+}
+
+func (cs *CombatSystem) beginTurn() {
+	c := cs.battle.Current()
+	if !components.IsAlive(c.GameEntity) {
+		cs.nextTurn()
+		return
+	}
+
+	canAct := true
+	if cs.ss != nil {
+		canAct = cs.ss.Turn(c.GameEntity)
+	}
+
+	cs.ev.Dispatch(EventTurnStarted, map[string]any{
+		"battle":     cs.battle,
+		"gameEntity": c.GameEntity,
+		"canAct":     canAct,
+	})
+
+	if !canAct || !components.IsAlive(c.GameEntity) {
+		cs.EndTurn()
+		return
+	}
+
+	if cs.phase.Can("player") {
+		cs.fire("player")
+		cs.openMenu(c.GameEntity)
+		return
+	}
+
+	cs.fire("ai")
+}
+
+// openMenu shows the bound menu, listing the abilities of ge when it knows
+// some, and Use when it carries items.
+func (cs *CombatSystem) openMenu(ge *components.GameEntity) {
+	cs.ms.Reset(cs.menu)
+	if cs.ab != nil && len(ge.Abilities) > 0 {
+		extra := make([]string, 0)
+		if cs.iv != nil && ge.Inventory != nil {
+			extra = append(extra, components.ActionUse)
+		}
+		cs.ab.SetMenu(cs.menu, ge, extra...)
+	}
+	cs.ms.Show(cs.menu)
+}
+
+// choose handles a click on the battle menu: Use opens the item menu, other
+// actions go through targeting when they can be resolved.
+func (cs *CombatSystem) choose(name string) {
+	actor := cs.battle.Current().GameEntity
+	if name == components.ActionUse && cs.iv != nil && cs.resolvers[name] == nil {
+		cs.openItems(actor)
+		return
+	}
+
+	if cs.ts == nil || cs.resolver(name) == nil {
+		cs.Act(cs.NewAction(actor, name))
+		return
+	}
+
+	cs.ms.Hide(cs.menu)
+	cs.pending = name
+	cs.item = nil
+	cs.fire("target")
+	cs.selection = cs.ts.Select(cs.battle, actor, cs.Targeting(name))
+}
+
+// chooseItem handles a click on the item menu, its last entry goes back to the
+// battle menu.
+func (cs *CombatSystem) chooseItem(index int) {
+	actor := cs.battle.Current().GameEntity
+	if index >= len(cs.items) {
+		cs.closeItems()
+		cs.openMenu(actor)
+		return
+	}
+
+	item := cs.items[index]
+	cs.closeItems()
+	if cs.ts == nil {
+		cs.Act(cs.NewItemAction(actor, item))
+		return
+	}
+
+	cs.pending = item.Name
+	cs.item = item
+	cs.fire("target")
+	cs.selection = cs.ts.Select(cs.battle, actor, item.Use.Target)
+}
+
+// openItems replaces the battle menu with the usable items of ge, followed by
+// a Back entry.
+func (cs *CombatSystem) openItems(ge *components.GameEntity) {
+	if ge.Inventory == nil || len(ge.Inventory.Usable()) == 0 {
+		fmt.Printf("CS:openItems - %s has no usable item\n", ge.Name)
+		cs.openMenu(ge)
+		return
+	}
+
+	cs.closeItems()
+	labels := make([]string, 0)
+	for _, s := range ge.Inventory.Usable() {
+		cs.items = append(cs.items, s.Item)
+		labels = append(labels, s.String())
+	}
+	labels = append(labels, ItemMenuBack)
+
+	cs.ms.Hide(cs.menu)
+	container := cs.menu.Container.Drawable.(*common.Texture)
+	cursor := cs.menu.Cursor.Drawable.(*common.Texture)
+	cs.itemMenu = cs.ms.NewMenu(cs.menu.Name+"-items", cs.menu.SpaceComponent, container, cursor, labels, cs.menu.Font, false)
+	cs.itemMenu.Container.SetZIndex(components.LayerUi)
+}
+
+func (cs *CombatSystem) closeItems() {
+	cs.items = nil
+	if cs.itemMenu != nil {
+		cs.ms.Destroy(cs.itemMenu)
+		cs.itemMenu = nil
+	}
+}
+
+// NewItemAction builds the use of an item by actor, resolved as the ability of
+// the item.
+func (cs *CombatSystem) NewItemAction(actor *components.GameEntity, item *components.Item) *components.Action {
+	var focus *components.GameEntity
+	if candidates := cs.battle.Candidates(actor, item.Use.Target.Mode); len(candidates) > 0 {
+		focus = candidates[0]
+	}
+
+	return &components.Action{
+		Name:    components.ActionUse,
+		Actor:   actor,
+		Targets: cs.battle.ResolveTargets(actor, item.Use.Target, focus, cs.Rand),
+		Data: map[string]any{
+			"item": item,
+		},
+	}
+}
+
+// NewAction builds an action of actor aimed at its targeting, focusing the
+// first candidate.
+func (cs *CombatSystem) NewAction(actor *components.GameEntity, name string) *components.Action {
+	var focus *components.GameEntity
+	if candidates := cs.battle.Candidates(actor, cs.Targeting(name).Mode); len(candidates) > 0 {
+		focus = candidates[0]
+	}
+
+	return cs.NewFocusedAction(actor, name, focus)
+}
+
+// NewFocusedAction builds an action of actor aimed at its targeting around
+// focus.
+func (cs *CombatSystem) NewFocusedAction(actor *components.GameEntity, name string, focus *components.GameEntity) *components.Action {
+	targeting := cs.Targeting(name)
+
+	return &components.Action{
+		Name:    name,
+		Actor:   actor,
+		Targets: cs.battle.ResolveTargets(actor, targeting, focus, cs.Rand),
+		Data:    make(map[string]any),
+	}
+}
+
+// DefaultAction attacks the first target of the Attack targeting.
+func (cs *CombatSystem) DefaultAction(battle *components.Battle, combatant *components.Combatant) *components.Action {
+	return cs.NewAction(combatant.GameEntity, components.ActionAttack)
+}
+
+// Act resolves the action of the current combatant and ends its turn. An
+// unknown or unaffordable action is ignored during a player turn so another
+// can be picked.
+func (cs *CombatSystem) Act(action *components.Action) {
+	resolver := cs.resolver(action.Name)
+	if item, ok := action.Data["item"].(*components.Item); ok {
+		resolver = cs.ResolveItem
+		if action.Actor.Inventory == nil || action.Actor.Inventory.Count(item.Name) == 0 {
+			fmt.Printf("CS:Act - %s has no %s\n", action.Actor.Name, item.Name)
+			resolver = nil
+		}
+	} else if resolver == nil {
+		fmt.Printf("CS:Act - No resolver for action %s\n", action.Name)
+	} else if a := cs.ability(action.Name); a != nil && !a.CanPay(action.Actor) {
+		fmt.Printf("CS:Act - %s cannot pay for %s\n", action.Actor.Name, action.Name)
+		resolver = nil
+	}
+
+	if resolver == nil {
+		if cs.playerTurn() {
+			if !cs.phase.Is(combatSelecting) {
+				cs.fire("select")
+			}
+			cs.openMenu(action.Actor)
+			return
+		}
+		cs.EndTurn()
+		return
+	}
+
+	if cs.playerTurn() {
+		cs.ms.Hide(cs.menu)
+	}
+	cs.fire("resolve")
+
+	resolver(cs.battle, action)
+	cs.ev.Dispatch(EventActionResolved, map[string]any{
+		"battle": cs.battle,
+		"action": action,
+	})
+
+	cs.EndTurn()
+}
+
+// ResolveAttack rolls a d20 to hit, a natural 1 misses and a natural 20
+// doubles the damage of ATK + 1d6 - DEF.
+func (cs *CombatSystem) ResolveAttack(battle *components.Battle, action *components.Action) {
+	for _, target := range action.Targets {
+		result := &components.ActionResult{Target: target}
+		action.Results = append(action.Results, result)
+
+		hit := components.NewDices(20, 1).RollDetailedWith(cs.Rand)
+		if hit.Fumble() {
+			result.Miss = true
+			continue
+		}
+
+		damage := components.NewDices(6, 1).RollWith(cs.Rand)
+		if action.Actor.Stats != nil {
+			damage += action.Actor.Stats.Get(components.StatAtk)
+		}
+		if target.Stats != nil {
+			damage -= target.Stats.Get(components.StatDef)
+		}
+		damage = math.Max(1, damage)
+
+		if hit.Critical() {
+			result.Critical = true
+			damage *= 2
+		}
+		damage = cs.elemental(result, components.ElementPhysical, damage)
+		result.Damage = damage
+
+		cs.Damage(target, damage)
+	}
+}
+
+// ResolveAbility pays the cost of the ability, then applies it.
+func (cs *CombatSystem) ResolveAbility(battle *components.Battle, action *components.Action) {
+	ability := cs.ability(action.Name)
+	cs.ab.Pay(action.Actor, ability)
+	cs.apply(ability, action)
+}
+
+// ResolveItem consumes the item of the action, then applies its ability.
+func (cs *CombatSystem) ResolveItem(battle *components.Battle, action *components.Action) {
+	item := action.Data["item"].(*components.Item)
+	if _, err := cs.iv.Use(action.Actor, item.Name); err != nil {
+		fmt.Printf("CS:ResolveItem - %s\n", err)
+		return
+	}
+
+	cs.apply(item.Use, action)
+}
+
+// UseItem resolves an item of actor on targets outside battles, such as a
+// Potion used from the field. During a battle items are used as actions.
+func (cs *CombatSystem) UseItem(actor *components.GameEntity, name string, targets ...*components.GameEntity) (*components.Action, error) {
+	if cs.battle != nil && !cs.battle.Over {
+		return nil, fmt.Errorf("CS:UseItem - %s can only use %s as a combat action", actor.Name, name)
+	}
+	if cs.iv == nil {
+		return nil, fmt.Errorf("CS:UseItem - no inventory system")
+	}
+	item := cs.iv.Items.Get(name)
+	if item == nil || !item.Usable() {
+		return nil, fmt.Errorf("CS:UseItem - %s is not a usable item", name)
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("CS:UseItem - %s used without target", name)
+	}
+	if _, err := cs.iv.Use(actor, name); err != nil {
+		return nil, err
+	}
+
+	action := &components.Action{
+		Name:    components.ActionUse,
+		Actor:   actor,
+		Targets: targets,
+		Data: map[string]any{
+			"item": item,
+		},
+	}
+	cs.apply(item.Use, action)
+	cs.ev.Dispatch(EventActionResolved, map[string]any{
+		"battle": cs.battle,
+		"action": action,
+	})
+
+	return action, nil
+}
+
+// apply deals the damage and statuses of the ability to every target.
+func (cs *CombatSystem) apply(ability *components.Ability, action *components.Action) {
+	action.Data["ability"] = ability
+	action.Data["element"] = ability.Element
+	action.Data["animation"] = ability.Animation
+
+	for _, target := range action.Targets {
+		result := &components.ActionResult{Target: target}
+		action.Results = append(action.Results, result)
+
+		if ability.ToHit {
+			hit := components.NewDices(20, 1).RollDetailedWith(cs.Rand)
+			if hit.Fumble() {
+				result.Miss = true
+				continue
+			}
+			result.Critical = hit.Critical()
+		}
+
+		if dice := ability.DamageDice(); dice != nil {
+			amount := dice.RollWith(cs.Rand)
+			if ability.Stat != "" && action.Actor.Stats != nil {
+				amount += action.Actor.Stats.Get(ability.Stat)
+			}
+			if ability.Defense != "" && target.Stats != nil {
+				amount -= target.Stats.Get(ability.Defense)
+			}
+			amount = math.Max(1, amount)
+
+			if result.Critical {
+				amount *= 2
+			}
+			if ability.Heal {
+				amount = -amount
+			} else {
+				amount = cs.elemental(result, ability.Element, amount)
+			}
+			result.Damage = amount
+
+			cs.Damage(target, amount)
+		}
+
+		for _, name := range ability.Cures {
+			if cs.ss != nil {
+				cs.ss.Cure(target, name)
+			}
+		}
+
+		for _, s := range ability.Statuses {
+			if cs.ss == nil || !components.IsAlive(target) {
+				break
+			}
+			if s.Chance == 0 || cs.Rand.Float64()*100 < s.Chance {
+				cs.ss.Apply(target, components.NewStatusEffect(s.Name, s.Duration))
+			}
+		}
+	}
+}
+
+// elemental scales damage by the affinity of the target with element, when
+// elements are loaded.
+func (cs *CombatSystem) elemental(result *components.ActionResult, element string, amount float64) float64 {
+	if cs.el == nil {
+		return amount
+	}
+
+	return cs.el.Apply(result, element, amount)
+}
+
+// Damage removes HP from ge, a negative amount heals.
+func (cs *CombatSystem) Damage(ge *components.GameEntity, amount float64) {
+	if ge.Stats == nil {
+		return
+	}
+
+	if cs.st != nil {
+		cs.st.AddPoolFrom(ge, components.StatHp, -amount, PoolSourceAction)
+		return
+	}
+	ge.Stats.AddPool(components.StatHp, -amount)
+}
+
+func (cs *CombatSystem) EndTurn() {
+	c := cs.battle.Current()
+	cs.fire("end")
+	if c.Gauge != nil {
+		c.Gauge.Reset()
+	}
+
+	if c.GameEntity.Stats != nil && cs.st != nil {
+		cs.st.Tick(c.GameEntity, 1)
+	}
+
+	cs.ev.Dispatch(EventTurnEnded, map[string]any{
+		"battle":     cs.battle,
+		"gameEntity": c.GameEntity,
+	})
+
+	if cs.checkEnd() {
+		return
+	}
+
+	cs.nextTurn()
+	if !cs.battle.Atb() {
+		cs.delay = CombatTurnDelay
+	}
+}
+
+// nextTurn moves to the next combatant of the queue, in ATB mode the next one
+// is picked from the ready queue once idle.
+func (cs *CombatSystem) nextTurn() {
+	if cs.battle.Atb() {
+		if !cs.phase.Is(combatIdle) {
+			cs.fire("end")
+		}
+		return
+	}
+
+	cs.battle.Turn++
+	if cs.battle.Turn >= len(cs.battle.Queue) {
+		cs.startRound()
+		return
+	}
+
+	cs.fire("begin")
+}
+
+func (cs *CombatSystem) checkEnd() bool {
+	standing := cs.battle.Standing()
+	if len(standing) > 1 {
+		return false
+	}
+
+	cs.battle.Over = true
+	victory := false
+	if len(standing) == 1 {
+		cs.battle.Winner = standing[0]
+		victory = standing[0].Player
+	}
+
+	if cs.menu != nil {
+		cs.ms.Hide(cs.menu)
+	}
+	cs.closeItems()
+	cs.removeBars()
+
+	cs.ev.Dispatch(EventCombatEnded, map[string]any{
+		"battle":  cs.battle,
+		"winner":  cs.battle.Winner,
+		"victory": victory,
+	})
+
+	return true
+}
+
+func (cs *CombatSystem) Debug() {
+	fmt.Printf("*** Combat System DEBUG ***\n")
+	if cs.battle == nil {
+		fmt.Printf("No battle\n\n")
+		return
+	}
+
+	fmt.Printf("Mode: %s, Round: %d, Turn: %d, Over: %t, Phase: %s\n", cs.battle.Mode, cs.battle.Round, cs.battle.Turn, cs.battle.Over, cs.phase.Current)
+	for i, c := range cs.battle.Queue {
+		current := ""
+		if i == cs.battle.Turn {
+			current = " <-"
+		}
+		hp := "-"
+		if c.GameEntity.Stats != nil {
+			hp = fmt.Sprintf("%g/%g", c.GameEntity.Stats.Pool(components.StatHp), c.GameEntity.Stats.Get(components.StatHp))
+		}
+		gauge := ""
+		if c.Gauge != nil {
+			gauge = fmt.Sprintf(" gauge %.0f/%.0f", c.Gauge.Value, c.Gauge.Max)
+		}
+		fmt.Printf("\t- %d %s (%s) HP %s alive: %t%s%s\n", c.Initiative, c.GameEntity.Name, c.Side.Name, hp, components.IsAlive(c.GameEntity), gauge, current)
+	}
+	fmt.Printf("\n")
+}
@@ -0,0 +1,142 @@
+package systems
+
+import (
+	"fmt"
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+	"log"
+	"math/rand"
+	"tools/components"
+)
+
+const (
+	EventCardDrawn      = "EventCardDrawn"
+	EventCardDiscarded  = "EventCardDiscarded"
+	EventCardMoved      = "EventCardMoved"
+	EventDeckReshuffled = "EventDeckReshuffled"
+)
+
+type DeckSystem struct {
+	ev    *EventSystem
+	decks map[string]*components.Deck
+}
+
+func (dk *DeckSystem) New(w *ecs.World) {
+	dk.decks = make(map[string]*components.Deck)
+
+	for _, system := range w.Systems() {
+		switch sys := system.(type) {
+		case *EventSystem:
+			dk.ev = sys
+		}
+	}
+
+	dk.ev.NewEvent(EventCardDrawn)
+	dk.ev.NewEvent(EventCardDiscarded)
+	dk.ev.NewEvent(EventCardMoved)
+	dk.ev.NewEvent(EventDeckReshuffled)
+}
+
+func (dk *DeckSystem) Update(dt float32) {
+	if engo.Input.Button("F7").JustPressed() {
+		dk.Debug()
+	}
+}
+
+func (dk *DeckSystem) Remove(e ecs.BasicEntity) {
+}
+
+func (dk *DeckSystem) NewDeck(name string, cards []*components.Card, rnd *rand.Rand) *components.Deck {
+	if dk.decks[name] != nil {
+		log.Fatalf("DK:NewDeck - Deck %s already exists\n", name)
+	}
+
+	deck := components.NewDeck(name, cards, rnd)
+	dk.decks[name] = deck
+
+	fmt.Printf("DK:NewDeck - Deck %s created with %d cards\n", name, len(cards))
+
+	return deck
+}
+
+func (dk *DeckSystem) Get(name string) *components.Deck {
+	return dk.decks[name]
+}
+
+func (dk *DeckSystem) Destroy(deck *components.Deck) {
+	delete(dk.decks, deck.Name)
+}
+
+func (dk *DeckSystem) Draw(deck *components.Deck, n int) []*components.Card {
+	drawn, reshuffled := deck.Draw(n)
+	if reshuffled {
+		dk.ev.Dispatch(EventDeckReshuffled, map[string]any{
+			"deck": deck,
+		})
+	}
+
+	for _, card := range drawn {
+		dk.ev.Dispatch(EventCardDrawn, map[string]any{
+			"deck": deck,
+			"card": card,
+		})
+	}
+
+	return drawn
+}
+
+func (dk *DeckSystem) Discard(deck *components.Deck, cards ...*components.Card) {
+	for _, card := range cards {
+		if err := deck.Discard(card); err != nil {
+			fmt.Printf("DK:Discard - %s\n", err)
+			continue
+		}
+
+		dk.ev.Dispatch(EventCardDiscarded, map[string]any{
+			"deck": deck,
+			"card": card,
+		})
+	}
+}
+
+func (dk *DeckSystem) DiscardHand(deck *components.Deck) {
+	for _, card := range deck.DiscardHand() {
+		dk.ev.Dispatch(EventCardDiscarded, map[string]any{
+			"deck": deck,
+			"card": card,
+		})
+	}
+}
+
+func (dk *DeckSystem) Move(deck *components.Deck, card *components.Card, from string, to string) {
+	if err := deck.Move(card, from, to); err != nil {
+		fmt.Printf("DK:Move - %s\n", err)
+		return
+	}
+
+	dk.ev.Dispatch(EventCardMoved, map[string]any{
+		"deck": deck,
+		"card": card,
+		"from": from,
+		"to":   to,
+	})
+}
+
+func (dk *DeckSystem) Reshuffle(deck *components.Deck) {
+	deck.Reshuffle()
+	dk.ev.Dispatch(EventDeckReshuffled, map[string]any{
+		"deck": deck,
+	})
+}
+
+func (dk *DeckSystem) Debug() {
+	fmt.Printf("*** Deck System DEBUG ***\n")
+	fmt.Printf("Instances: %d\n", len(dk.decks))
+	for _, d := range dk.decks {
+		fmt.Printf("\t- Deck %s\n", d.Name)
+		for name, p := range d.Piles {
+			fmt.Printf("\t\t- %s: %d %v\n", name, len(p), p)
+		}
+	}
+	fmt.Printf("\n")
+}
@@ -4,16 +4,29 @@ import (
 	"fmt"
 	"github.com/EngoEngine/ecs"
 	"github.com/EngoEngine/engo"
+	"tools/components"
 )
 
 const (
 	EventStartDrag = "EventStartDrag"
 	EventStopDrag  = "EventStopDrag"
+
+	DragEnabled  = "enabled"
+	DragDragging = "dragging"
+	DragDisabled = "disabled"
 )
 
+// DragSystem dispatches EventStartDrag when a draggable entity is clicked. The
+// system owning the entity, such as the menu system, can Claim it to move it
+// with Follow until it Drops it, otherwise the drag system moves the entity and
+// its children itself until the mouse is released.
 type DragSystem struct {
-	em *EntityManager
-	ev *EventSystem
+	em      *EntityManager
+	ev      *EventSystem
+	sm      *FsmSystem
+	State   *components.Fsm
+	dragged *components.Entity
+	claimed bool
 }
 
 func (ds *DragSystem) New(w *ecs.World) {
@@ -23,74 +36,129 @@ func (ds *DragSystem) New(w *ecs.World) {
 			ds.em = sys
 		case *EventSystem:
 			ds.ev = sys
+		case *FsmSystem:
+			ds.sm = sys
 		}
 	}
 
 	ds.ev.NewEvent(EventStartDrag)
 	ds.ev.NewEvent(EventStopDrag)
+
+	ds.State = components.NewFsm("drag", DragEnabled).
+		AddState(DragDragging, func(from string, to string) {
+			ds.Grab(ds.dragged)
+			for _, ce := range ds.children(ds.dragged) {
+				ds.Grab(ce)
+			}
+		}, func(from string, to string) {
+			for _, ce := range ds.children(ds.dragged) {
+				ce.XOff = 0
+				ce.YOff = 0
+			}
+			ds.Drop(ds.dragged)
+			ds.dragged = nil
+		}).
+		AddState(DragDisabled, nil, nil).
+		AddTransition("drag", []string{DragEnabled}, DragDragging, nil).
+		AddTransition("drop", []string{DragDragging}, DragEnabled, nil).
+		AddTransition("disable", []string{DragEnabled, DragDragging}, DragDisabled, nil).
+		AddTransition("enable", []string{DragDisabled}, DragEnabled, nil)
+	if ds.sm != nil {
+		ds.sm.Add(ds.State)
+	}
 }
 
 func (ds *DragSystem) Update(dt float32) {
-	eStartDrag := ds.ev.Get(EventStartDrag)
-	eStopDrag := ds.ev.Get(EventStopDrag)
-	if eStartDrag == nil || !eStartDrag.Active || eStopDrag == nil || !eStopDrag.Active {
+	if ds.State.Is(DragDragging) {
+		if ds.dragged.MouseComponent.Released {
+			ds.State.Fire("drop")
+			return
+		}
+		ds.Follow(ds.dragged)
+		for _, ce := range ds.children(ds.dragged) {
+			ds.Follow(ce)
+		}
+		return
+	}
+	if !ds.State.Is(DragEnabled) {
 		return
 	}
 
 	for _, e := range ds.em.GetInstances() {
-		if !e.IsDraggable {
+		if !e.IsDraggable || !e.MouseComponent.Clicked {
 			continue
 		}
 
-		if e.MouseComponent.Clicked {
-			for _, c := range e.Children() {
-				ce := ds.em.Get(c)
-
-				// Prevent drag if a children has been clicked
-				if ce.MouseComponent.Clicked {
-					fmt.Printf("Cancel drag because %d has been clicked\n", ce.ID())
-					return
-				}
-
-				if ce != nil {
-					ce.StartDrag()
-				}
+		// Prevent drag if a children has been clicked
+		for _, ce := range ds.children(e) {
+			if ce.MouseComponent.Clicked {
+				fmt.Printf("Cancel drag because %d has been clicked\n", ce.ID())
+				return
 			}
-			e.StartDrag()
-			ds.ev.Dispatch(EventStartDrag, map[string]any{
-				"entity": e,
-			})
-			return
 		}
 
-		if e.MouseComponent.Released && e.Drag {
-			for _, c := range e.Children() {
-				ce := ds.em.Get(c)
-				ce.StopDrag()
-			}
-			e.StopDrag()
-			ds.ev.Dispatch(EventStopDrag, map[string]any{
-				"entity": e,
-			})
-			return
+		ds.claimed = false
+		ds.ev.Dispatch(EventStartDrag, map[string]any{
+			"entity": e,
+		})
+		if !ds.claimed {
+			ds.dragged = e
+			ds.State.Fire("drag")
 		}
+		return
+	}
+}
 
-		if e.Drag {
-			e.SpaceComponent.Position.Set(engo.Input.Mouse.X-e.XOff, engo.Input.Mouse.Y-e.YOff)
+func (ds *DragSystem) children(e *components.Entity) []*components.Entity {
+	children := make([]*components.Entity, 0)
+	for _, c := range e.Children() {
+		if ce := ds.em.Get(c); ce != nil {
+			children = append(children, ce)
 		}
 	}
+
+	return children
+}
+
+// Claim tells, from an EventStartDrag listener, that the owner of the entity
+// moves it, so the drag system leaves it alone.
+func (ds *DragSystem) Claim() {
+	ds.claimed = true
+}
+
+// Grab keeps the offset of the mouse from e, so that Follow moves e along.
+func (ds *DragSystem) Grab(e *components.Entity) {
+	e.XOff = engo.Input.Mouse.X - e.SpaceComponent.Position.X
+	e.YOff = engo.Input.Mouse.Y - e.SpaceComponent.Position.Y
+}
+
+func (ds *DragSystem) Follow(e *components.Entity) {
+	e.SpaceComponent.Position.Set(engo.Input.Mouse.X-e.XOff, engo.Input.Mouse.Y-e.YOff)
+}
+
+// Drop releases e and dispatches EventStopDrag.
+func (ds *DragSystem) Drop(e *components.Entity) {
+	e.XOff = 0
+	e.YOff = 0
+	ds.ev.Dispatch(EventStopDrag, map[string]any{
+		"entity": e,
+	})
 }
 
 func (ds *DragSystem) Disable() {
-	ds.ev.Disable(EventStartDrag)
-	ds.ev.Disable(EventStopDrag)
+	if err := ds.State.Fire("disable"); err != nil {
+		fmt.Printf("DS:Disable - %s\n", err)
+		return
+	}
 	fmt.Printf("DS:Disable - Drag system disabled\n")
 }
 
 func (ds *DragSystem) Enable() {
-	ds.ev.Enable(EventStartDrag)
-	ds.ev.Enable(EventStopDrag)
-	fmt.Printf("DS:Disable - Drag system enabled\n")
+	if err := ds.State.Fire("enable"); err != nil {
+		fmt.Printf("DS:Enable - %s\n", err)
+		return
+	}
+	fmt.Printf("DS:Enable - Drag system enabled\n")
 }
 
 func (ds *DragSystem) Remove(e ecs.BasicEntity) {
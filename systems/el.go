@@ -0,0 +1,137 @@
+package systems
+
+import (
+	"fmt"
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+	"math"
+	"tools/components"
+)
+
+// ElementSystem holds the damage elements and the resistance matrices of
+// entities, the combat and status systems scale damage through it.
+type ElementSystem struct {
+	ab       *AbilitySystem
+	iv       *InventorySystem
+	pf       *PrefabSystem
+	ss       *StatusSystem
+	Elements *components.Elements
+}
+
+func (el *ElementSystem) New(w *ecs.World) {
+	el.Elements = components.NewElements()
+
+	for _, system := range w.Systems() {
+		switch sys := system.(type) {
+		case *AbilitySystem:
+			el.ab = sys
+		case *InventorySystem:
+			el.iv = sys
+		case *PrefabSystem:
+			el.pf = sys
+		case *StatusSystem:
+			el.ss = sys
+		}
+	}
+
+	if el.ss != nil {
+		el.ss.SetElements(el)
+	}
+}
+
+func (el *ElementSystem) Update(dt float32) {
+	if engo.Input.Button("5").JustPressed() {
+		el.Debug()
+	}
+}
+
+func (el *ElementSystem) Remove(e ecs.BasicEntity) {
+}
+
+// Load reads the elements of dir and checks the elements of the loaded
+// abilities, items and prefabs.
+func (el *ElementSystem) Load(dir string) error {
+	if err := el.Elements.LoadDir(dir); err != nil {
+		return err
+	}
+
+	if el.ab != nil {
+		for _, name := range el.ab.Abilities.Names() {
+			if err := el.checkAbility(el.ab.Get(name)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if el.iv != nil {
+		for _, name := range el.iv.Items.Names() {
+			if use := el.iv.Items.Get(name).Use; use != nil {
+				if err := el.checkAbility(use); err != nil {
+					return fmt.Errorf("EL:Load - item %s: %s", name, err)
+				}
+			}
+		}
+	}
+
+	if el.pf != nil {
+		for _, name := range el.pf.Prefabs.Names() {
+			prefab, err := el.pf.Prefabs.Resolve(name)
+			if err != nil {
+				return err
+			}
+			if prefab.Resistance != "" && el.Elements.Resistance(prefab.Resistance) == nil {
+				return fmt.Errorf("EL:Load - prefab %s has unknown resistance %s", name, prefab.Resistance)
+			}
+			if err := el.Elements.Check(prefab.Resistances); err != nil {
+				return fmt.Errorf("EL:Load - prefab %s resists %s", name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (el *ElementSystem) checkAbility(ability *components.Ability) error {
+	if ability.Element != "" && el.Elements.Get(ability.Element) == nil {
+		return fmt.Errorf("EL:Load - ability %s has unknown element %s", ability.Name, ability.Element)
+	}
+
+	return nil
+}
+
+// Apply scales amount of damage by the multiplier of element against the
+// target of result, and records the element and its affinity in result.
+func (el *ElementSystem) Apply(result *components.ActionResult, element string, amount float64) float64 {
+	if element == "" {
+		element = components.ElementPhysical
+	}
+
+	amount, result.Affinity = el.Scale(result.Target, element, amount)
+	result.Element = element
+
+	return amount
+}
+
+// Scale returns amount of damage scaled by the multiplier of element against
+// ge, and the affinity of ge with element.
+func (el *ElementSystem) Scale(ge *components.GameEntity, element string, amount float64) (float64, string) {
+	multiplier := el.Elements.Multiplier(ge, element)
+
+	return math.Round(amount * multiplier), components.Affinity(multiplier)
+}
+
+func (el *ElementSystem) Debug() {
+	fmt.Printf("*** Element System DEBUG ***\n")
+	fmt.Printf("Elements: %v\n", el.Elements.Names())
+	for _, name := range el.Elements.ResistanceNames() {
+		r := el.Elements.Resistance(name)
+		fmt.Printf("\t- %s:", name)
+		for _, element := range el.Elements.Names() {
+			if value, ok := r.Values[element]; ok {
+				fmt.Printf(" %s %g (%s)", element, value, components.Affinity(value))
+			}
+		}
+		fmt.Printf("\n")
+	}
+	fmt.Printf("\n")
+}
@@ -51,7 +51,6 @@ func (es *EventSystem) NewEvent(name string) {
 	e := components.Event{
 		BasicEntity: ecs.NewBasic(),
 		Name:        name,
-		Active:      true,
 		Data:        make(map[string]interface{}),
 	}
 
@@ -91,36 +90,6 @@ func (es *EventSystem) Dispatch(name string, data map[string]any) {
 	fmt.Printf("ES:Dispatch: %d %s dispatched\n", e.ID(), e.Name)
 }
 
-func (es *EventSystem) Disable(name string) {
-	if es.events[name] == nil {
-		log.Fatalf("ES:Disable: Unknow event %s\n", name)
-	}
-
-	e := es.events[name]
-	hear := es.hears[e.MessageHandlerId]
-	if hear == nil {
-		return
-	}
-	hear.Active = true
-
-	fmt.Printf("ES:Disable: %d %s disabled\n", e.ID(), e.Name)
-}
-
-func (es *EventSystem) Enable(name string) {
-	if es.events[name] == nil {
-		log.Fatalf("ES:Disable: Unknow event %s\n", name)
-	}
-
-	e := es.events[name]
-	hear := es.hears[e.MessageHandlerId]
-	if hear == nil {
-		return
-	}
-	hear.Active = false
-
-	fmt.Printf("ES:Disable: %d %s enabled\n", e.ID(), e.Name)
-}
-
 func (es *EventSystem) Debug() {
 	fmt.Printf("*** Event Manager DEBUG ***\n")
 	fmt.Printf("Created: %d\n", len(es.events))
@@ -0,0 +1,75 @@
+package systems
+
+import (
+	"fmt"
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+	"tools/components"
+)
+
+// FlowSystem moves the game from the title to the field, then between the
+// field and battles as they start and end.
+type FlowSystem struct {
+	ev    *EventSystem
+	sm    *FsmSystem
+	cs    *CombatSystem
+	State *components.Fsm
+}
+
+func (fl *FlowSystem) New(w *ecs.World) {
+	for _, system := range w.Systems() {
+		switch sys := system.(type) {
+		case *EventSystem:
+			fl.ev = sys
+		case *FsmSystem:
+			fl.sm = sys
+		case *CombatSystem:
+			fl.cs = sys
+		}
+	}
+
+	fl.State = components.NewFsm("game", components.FlowTitle).
+		AddState(components.FlowField, nil, nil).
+		AddState(components.FlowBattle, nil, nil).
+		AddTransition(components.FlowStart, []string{components.FlowTitle}, components.FlowField, nil).
+		AddTransition(components.FlowEncounter, []string{components.FlowField}, components.FlowBattle, nil).
+		AddTransition(components.FlowLeave, []string{components.FlowBattle}, components.FlowField, func() bool {
+			return fl.cs == nil || fl.cs.GetBattle() == nil || fl.cs.GetBattle().Over
+		})
+	if fl.sm != nil {
+		fl.sm.Add(fl.State)
+	}
+
+	fl.ev.Listen(EventCombatStarted, func(msg engo.Message) {
+		fl.fire(components.FlowEncounter)
+	})
+	fl.ev.Listen(EventCombatEnded, func(msg engo.Message) {
+		fl.fire(components.FlowLeave)
+	})
+}
+
+func (fl *FlowSystem) Update(dt float32) {
+	if engo.Input.Button("6").JustPressed() {
+		fl.Debug()
+	}
+}
+
+func (fl *FlowSystem) Remove(e ecs.BasicEntity) {
+}
+
+// Start leaves the title for the field.
+func (fl *FlowSystem) Start() {
+	fl.fire(components.FlowStart)
+}
+
+func (fl *FlowSystem) fire(transition string) {
+	if err := fl.State.Fire(transition); err != nil {
+		fmt.Printf("FL:fire - %s\n", err)
+	}
+}
+
+func (fl *FlowSystem) Debug() {
+	fmt.Printf("*** Flow System DEBUG ***\n")
+	fmt.Printf("State: %s, history %v\n", fl.State.Current, fl.State.History)
+	fmt.Printf("\n")
+}
@@ -0,0 +1,182 @@
+package systems
+
+import (
+	"fmt"
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/engo/common"
+	"image/color"
+	"math"
+	"tools/components"
+)
+
+const (
+	FloatingTextFont     = "Roboto-Regular.ttf"
+	FloatingTextSize     = 22
+	FloatingTextDuration = 1.0
+	FloatingTextSpeed    = 40
+	FloatingTextSpacing  = 24
+
+	TextMiss     = "MISS"
+	TextCritical = "CRIT!"
+	TextWeak     = "Weak!"
+	TextResist   = "Resist"
+	TextImmune   = "Immune"
+	TextAbsorb   = "Absorb"
+)
+
+type FloatingTextSystem struct {
+	em    *EntityManager
+	ev    *EventSystem
+	ui    *UiSystem
+	texts []*components.FloatingText
+}
+
+func (fs *FloatingTextSystem) New(w *ecs.World) {
+	fs.texts = make([]*components.FloatingText, 0)
+
+	for _, system := range w.Systems() {
+		switch sys := system.(type) {
+		case *EntityManager:
+			fs.em = sys
+		case *EventSystem:
+			fs.ev = sys
+		case *UiSystem:
+			fs.ui = sys
+		}
+	}
+
+	fs.ev.Listen(EventActionResolved, func(msg engo.Message) {
+		evt := msg.(*components.Event)
+		action := evt.Data["action"].(*components.Action)
+
+		for _, r := range action.Results {
+			fs.Result(r)
+		}
+	})
+
+	fs.ev.Listen(EventStatusTriggered, func(msg engo.Message) {
+		evt := msg.(*components.Event)
+		ge := evt.Data["gameEntity"].(*components.GameEntity)
+		damage := evt.Data["damage"].(float64)
+		affinity := evt.Data["affinity"].(string)
+
+		fs.Affinity(ge, affinity)
+		fs.Damage(ge, damage)
+	})
+}
+
+func (fs *FloatingTextSystem) Update(dt float32) {
+	if engo.Input.Button("F12").JustPressed() {
+		fs.Debug()
+	}
+
+	alive := fs.texts[:0]
+	for _, ft := range fs.texts {
+		ft.Age += dt
+		if ft.Done() {
+			fs.em.Remove(ft.Entity.BasicEntity)
+			continue
+		}
+
+		a := uint8(255 * ft.Alpha())
+		ft.Entity.Position.Y -= FloatingTextSpeed * dt
+		ft.Entity.RenderComponent.Color = color.RGBA{R: a, G: a, B: a, A: a}
+		alive = append(alive, ft)
+	}
+	fs.texts = alive
+}
+
+func (fs *FloatingTextSystem) Remove(e ecs.BasicEntity) {
+	for i, ft := range fs.texts {
+		if ft.Entity.ID() == e.ID() {
+			fs.texts = append(fs.texts[:i], fs.texts[i+1:]...)
+			return
+		}
+	}
+}
+
+// Result shows the outcome of an action on its target: a miss, a critical hit
+// and the elemental affinity above its damage, or the damage or heal alone.
+func (fs *FloatingTextSystem) Result(r *components.ActionResult) {
+	if r.Miss {
+		fs.Spawn(r.Target, TextMiss, components.ColorMiss)
+		return
+	}
+
+	if r.Critical {
+		fs.Spawn(r.Target, TextCritical, components.ColorDamages)
+	}
+	fs.Affinity(r.Target, r.Affinity)
+	fs.Damage(r.Target, r.Damage)
+}
+
+// Affinity shows the effect of an element on ge, normal damage shows nothing.
+func (fs *FloatingTextSystem) Affinity(ge *components.GameEntity, affinity string) {
+	switch affinity {
+	case components.AffinityWeak:
+		fs.Spawn(ge, TextWeak, components.ColorWeak)
+	case components.AffinityResist:
+		fs.Spawn(ge, TextResist, components.ColorResist)
+	case components.AffinityImmune:
+		fs.Spawn(ge, TextImmune, components.ColorResist)
+	case components.AffinityAbsorb:
+		fs.Spawn(ge, TextAbsorb, components.ColorHeal)
+	}
+}
+
+// Damage shows "-N" for damage and "+N" for a negative amount, which heals.
+func (fs *FloatingTextSystem) Damage(ge *components.GameEntity, amount float64) {
+	amount = math.Round(amount)
+	switch {
+	case amount > 0:
+		fs.Spawn(ge, fmt.Sprintf("-%g", amount), components.ColorDamages)
+	case amount < 0:
+		fs.Spawn(ge, fmt.Sprintf("+%g", -amount), components.ColorHeal)
+	}
+}
+
+// Spawn shows txt above the entity of ge, stacked over the texts still rising
+// above it.
+func (fs *FloatingTextSystem) Spawn(ge *components.GameEntity, txt string, clr color.Color) *components.FloatingText {
+	if ge.Entity == nil {
+		fmt.Printf("FS:Spawn - %s has no entity to show \"%s\"\n", ge.Name, txt)
+		return nil
+	}
+
+	stacked := 0
+	for _, ft := range fs.texts {
+		if ft.Target == ge {
+			stacked++
+		}
+	}
+
+	e := fs.ui.NewText(txt, common.SpaceComponent{}, FloatingTextSize, FloatingTextFont, clr)
+	w, h, _ := e.Drawable.(common.Text).Font.TextDimensions(txt)
+	e.Ref = fmt.Sprintf("floating-text-%d", e.ID())
+	e.Width = float32(w)
+	e.Height = float32(h)
+	e.Position = engo.Point{
+		X: ge.Entity.Position.X + (ge.Entity.Width-e.Width)/2,
+		Y: ge.Entity.Position.Y - e.Height - float32(stacked)*FloatingTextSpacing,
+	}
+	e.SetZIndex(components.LayerFront)
+
+	ft := &components.FloatingText{
+		Entity:   e,
+		Target:   ge,
+		Duration: FloatingTextDuration,
+	}
+	fs.texts = append(fs.texts, ft)
+
+	return ft
+}
+
+func (fs *FloatingTextSystem) Debug() {
+	fmt.Printf("*** Floating Text System DEBUG ***\n")
+	fmt.Printf("Instances: %d\n", len(fs.texts))
+	for _, ft := range fs.texts {
+		fmt.Printf("\t- %s above %s, %.2f/%.2fs\n", ft.Entity.Drawable.(common.Text).Text, ft.Target.Name, ft.Age, ft.Duration)
+	}
+	fmt.Printf("\n")
+}
@@ -0,0 +1,247 @@
+package systems
+
+import (
+	"fmt"
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+	"tools/components"
+)
+
+const (
+	EventItemAdded      = "EventItemAdded"
+	EventItemUsed       = "EventItemUsed"
+	EventItemDropped    = "EventItemDropped"
+	EventItemEquipped   = "EventItemEquipped"
+	EventItemUnequipped = "EventItemUnequipped"
+)
+
+type InventorySystem struct {
+	ev    *EventSystem
+	st    *StatsSystem
+	Items *components.Items
+}
+
+func (iv *InventorySystem) New(w *ecs.World) {
+	iv.Items = components.NewItems()
+
+	for _, system := range w.Systems() {
+		switch sys := system.(type) {
+		case *EventSystem:
+			iv.ev = sys
+		case *StatsSystem:
+			iv.st = sys
+		}
+	}
+
+	iv.ev.NewEvent(EventItemAdded)
+	iv.ev.NewEvent(EventItemUsed)
+	iv.ev.NewEvent(EventItemDropped)
+	iv.ev.NewEvent(EventItemEquipped)
+	iv.ev.NewEvent(EventItemUnequipped)
+}
+
+func (iv *InventorySystem) Update(dt float32) {
+	if engo.Input.Button("F5").JustPressed() {
+		iv.Debug()
+	}
+}
+
+func (iv *InventorySystem) Remove(e ecs.BasicEntity) {
+}
+
+func (iv *InventorySystem) Load(dir string) error {
+	return iv.Items.LoadDir(dir)
+}
+
+// Share gives the same inventory to every member of a party.
+func (iv *InventorySystem) Share(inventory *components.Inventory, party ...*components.GameEntity) {
+	for _, ge := range party {
+		ge.Inventory = inventory
+	}
+}
+
+// Give adds count items to the inventory of ge and returns how many fit.
+func (iv *InventorySystem) Give(ge *components.GameEntity, name string, count int) (int, error) {
+	item := iv.Items.Get(name)
+	if item == nil {
+		return 0, fmt.Errorf("IV:Give - unknown item %s", name)
+	}
+	if ge.Inventory == nil {
+		ge.Inventory = components.NewInventory(0)
+	}
+
+	added := ge.Inventory.Add(item, count)
+	if added > 0 {
+		iv.ev.Dispatch(EventItemAdded, map[string]any{
+			"gameEntity": ge,
+			"item":       item,
+			"count":      added,
+		})
+	}
+
+	return added, nil
+}
+
+// Use consumes one usable item of ge, its effect is resolved by the combat
+// action pipeline: as an action in battle, or by CombatSystem.UseItem.
+func (iv *InventorySystem) Use(ge *components.GameEntity, name string) (*components.Item, error) {
+	item := iv.get(ge, name)
+	if item == nil {
+		return nil, fmt.Errorf("IV:Use - %s has no %s", ge.Name, name)
+	}
+	if !item.Usable() {
+		return nil, fmt.Errorf("IV:Use - %s is not usable", name)
+	}
+
+	ge.Inventory.Remove(name, 1)
+	iv.ev.Dispatch(EventItemUsed, map[string]any{
+		"gameEntity": ge,
+		"item":       item,
+	})
+
+	return item, nil
+}
+
+func (iv *InventorySystem) Drop(ge *components.GameEntity, name string, count int) (int, error) {
+	item := iv.get(ge, name)
+	if item == nil {
+		return 0, fmt.Errorf("IV:Drop - %s has no %s", ge.Name, name)
+	}
+	if item.Kind == components.ItemKey {
+		return 0, fmt.Errorf("IV:Drop - key item %s cannot be dropped", name)
+	}
+
+	dropped := ge.Inventory.Remove(name, count)
+	iv.ev.Dispatch(EventItemDropped, map[string]any{
+		"gameEntity": ge,
+		"item":       item,
+		"count":      dropped,
+	})
+
+	return dropped, nil
+}
+
+// Equip moves an equipment from the inventory of ge to the first slot
+// accepting it.
+func (iv *InventorySystem) Equip(ge *components.GameEntity, name string) error {
+	item := iv.get(ge, name)
+	if item == nil {
+		return fmt.Errorf("IV:Equip - %s has no %s", ge.Name, name)
+	}
+
+	slot, err := ge.SlotFor(item)
+	if err != nil {
+		return err
+	}
+
+	return iv.EquipTo(ge, name, slot)
+}
+
+// EquipTo moves an equipment from the inventory of ge to slot and applies its
+// stat modifiers, the item previously in the slot goes back to the inventory.
+func (iv *InventorySystem) EquipTo(ge *components.GameEntity, name string, slot string) error {
+	item := iv.get(ge, name)
+	if item == nil {
+		return fmt.Errorf("IV:EquipTo - %s has no %s", ge.Name, name)
+	}
+	if err := ge.CanEquip(item, slot); err != nil {
+		return err
+	}
+
+	ge.Inventory.Remove(name, 1)
+	if ge.Equipment[slot] != nil {
+		if err := iv.Unequip(ge, slot); err != nil {
+			ge.Inventory.Add(item, 1)
+			return err
+		}
+	}
+
+	if ge.Equipment == nil {
+		ge.Equipment = make(map[string]*components.Item)
+	}
+	ge.Equipment[slot] = item
+	if ge.Stats != nil {
+		for _, m := range item.NewModifiers(components.EquipSource(slot)) {
+			if iv.st != nil {
+				iv.st.AddModifier(ge, m)
+				continue
+			}
+			ge.Stats.AddModifier(m)
+		}
+	}
+
+	iv.ev.Dispatch(EventItemEquipped, map[string]any{
+		"gameEntity": ge,
+		"item":       item,
+		"slot":       slot,
+	})
+
+	return nil
+}
+
+// Unequip moves the equipment in slot back to the inventory of ge and removes
+// its stat modifiers.
+func (iv *InventorySystem) Unequip(ge *components.GameEntity, slot string) error {
+	item := ge.Equipment[slot]
+	if item == nil {
+		return fmt.Errorf("IV:Unequip - %s has nothing in slot %s", ge.Name, slot)
+	}
+	if ge.Inventory == nil {
+		ge.Inventory = components.NewInventory(0)
+	}
+	if ge.Inventory.Add(item, 1) == 0 {
+		return fmt.Errorf("IV:Unequip - inventory of %s is full", ge.Name)
+	}
+
+	delete(ge.Equipment, slot)
+	if ge.Stats != nil {
+		if iv.st != nil {
+			iv.st.RemoveSource(ge, components.EquipSource(slot))
+		} else {
+			ge.Stats.RemoveSource(components.EquipSource(slot))
+		}
+	}
+
+	iv.ev.Dispatch(EventItemUnequipped, map[string]any{
+		"gameEntity": ge,
+		"item":       item,
+		"slot":       slot,
+	})
+
+	return nil
+}
+
+// Compare returns the stat deltas of ge if it equipped the item named name,
+// in the slot Equip would choose.
+func (iv *InventorySystem) Compare(ge *components.GameEntity, name string) ([]components.StatDelta, error) {
+	item := iv.Items.Get(name)
+	if item == nil {
+		return nil, fmt.Errorf("IV:Compare - unknown item %s", name)
+	}
+
+	slot, err := ge.SlotFor(item)
+	if err != nil {
+		return nil, err
+	}
+
+	return ge.Compare(item, slot), nil
+}
+
+func (iv *InventorySystem) get(ge *components.GameEntity, name string) *components.Item {
+	if ge.Inventory == nil {
+		return nil
+	}
+
+	return ge.Inventory.Get(name)
+}
+
+func (iv *InventorySystem) Debug() {
+	fmt.Printf("*** Inventory System DEBUG ***\n")
+	names := iv.Items.Names()
+	fmt.Printf("Items: %d\n", len(names))
+	for _, name := range names {
+		item := iv.Items.Get(name)
+		fmt.Printf("\t- %s (%s) stack %d, value %d\n", item.Name, item.Kind, item.MaxStack(), item.Value)
+	}
+	fmt.Printf("\n")
+}
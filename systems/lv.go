@@ -0,0 +1,270 @@
+package systems
+
+import (
+	"fmt"
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/engo/common"
+	"image/color"
+	"math/rand"
+	"tools/components"
+)
+
+const (
+	EventXpGained = "EventXpGained"
+	EventLevelUp  = "EventLevelUp"
+
+	LevelSummaryFont       = "Roboto-Regular.ttf"
+	LevelSummaryFontSize   = 22
+	LevelSummaryWidth      = 320
+	LevelSummaryLineHeight = 36
+	LevelSummaryOk         = "OK"
+)
+
+type LevelSystem struct {
+	ev           *EventSystem
+	st           *StatsSystem
+	ab           *AbilitySystem
+	ms           *MenuSystem
+	ui           *UiSystem
+	Rand         *rand.Rand
+	Progressions *components.Progressions
+	summary      *components.Menu
+}
+
+func (lv *LevelSystem) New(w *ecs.World) {
+	lv.Progressions = components.NewProgressions()
+	lv.Rand = components.GameStreams.Get(components.StreamLevel).Rand
+
+	for _, system := range w.Systems() {
+		switch sys := system.(type) {
+		case *EventSystem:
+			lv.ev = sys
+		case *StatsSystem:
+			lv.st = sys
+		case *AbilitySystem:
+			lv.ab = sys
+		case *MenuSystem:
+			lv.ms = sys
+		case *UiSystem:
+			lv.ui = sys
+		}
+	}
+
+	lv.ev.NewEvent(EventXpGained)
+	lv.ev.NewEvent(EventLevelUp)
+
+	lv.ev.Listen(EventMenuItemClicked, func(msg engo.Message) {
+		evt := msg.(*components.Event)
+		menu := evt.Data["menu"].(*components.Menu)
+		index := evt.Data["index"].(int)
+
+		if menu == lv.summary && lv.ms.ItemText(menu, index) == LevelSummaryOk {
+			lv.CloseSummary()
+		}
+	})
+}
+
+func (lv *LevelSystem) Update(dt float32) {
+	if engo.Input.Button("1").JustPressed() {
+		lv.Debug()
+	}
+}
+
+func (lv *LevelSystem) Remove(e ecs.BasicEntity) {
+}
+
+// Load reads the progressions of dir and checks that the abilities they
+// unlock exist.
+func (lv *LevelSystem) Load(dir string) error {
+	if err := lv.Progressions.LoadDir(dir); err != nil {
+		return err
+	}
+	if lv.ab == nil {
+		return nil
+	}
+
+	for _, name := range lv.Progressions.Names() {
+		p := lv.Progressions.Get(name)
+		for _, ability := range p.Unlocked(p.Curve.MaxLevel()) {
+			if lv.ab.Get(ability) == nil {
+				return fmt.Errorf("LV:Load - progression %s unlocks unknown ability %s", name, ability)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (lv *LevelSystem) Get(ge *components.GameEntity) (*components.Progression, error) {
+	p := lv.Progressions.Get(ge.Progression)
+	if p == nil {
+		return nil, fmt.Errorf("LV:Get - %s has unknown progression %q", ge.Name, ge.Progression)
+	}
+
+	return p, nil
+}
+
+// SetLevel puts ge at the start of level and teaches it the abilities
+// unlocked so far, without rolling stat growth.
+func (lv *LevelSystem) SetLevel(ge *components.GameEntity, level int) error {
+	p, err := lv.Get(ge)
+	if err != nil {
+		return err
+	}
+	if level < 1 || level > p.Curve.MaxLevel() {
+		return fmt.Errorf("LV:SetLevel - level %d is out of 1..%d", level, p.Curve.MaxLevel())
+	}
+
+	ge.Level = level
+	ge.Xp = p.Curve.XpFor(level)
+	for _, ability := range p.Unlocked(level) {
+		if !ge.Knows(ability) {
+			ge.Abilities = append(ge.Abilities, ability)
+		}
+	}
+
+	return nil
+}
+
+// AddXp gives xp to ge and levels it up as many times as its curve allows,
+// returning the gains of each new level.
+func (lv *LevelSystem) AddXp(ge *components.GameEntity, xp int) ([]*components.LevelGain, error) {
+	p, err := lv.Get(ge)
+	if err != nil {
+		return nil, err
+	}
+	if xp < 0 {
+		return nil, fmt.Errorf("LV:AddXp - negative xp %d", xp)
+	}
+
+	if ge.Level < 1 {
+		ge.Level = 1
+	}
+	if ge.Xp < p.Curve.XpFor(ge.Level) {
+		ge.Xp = p.Curve.XpFor(ge.Level)
+	}
+	ge.Xp += xp
+
+	lv.ev.Dispatch(EventXpGained, map[string]any{
+		"gameEntity": ge,
+		"xp":         xp,
+	})
+
+	gains := make([]*components.LevelGain, 0)
+	for ge.Level < p.Curve.MaxLevel() && ge.Xp >= p.Curve.XpFor(ge.Level+1) {
+		gains = append(gains, lv.levelUp(ge, p))
+	}
+
+	return gains, nil
+}
+
+// Next returns the XP ge still needs to reach its next level, 0 at the max
+// level.
+func (lv *LevelSystem) Next(ge *components.GameEntity) int {
+	p, err := lv.Get(ge)
+	if err != nil || ge.Level >= p.Curve.MaxLevel() {
+		return 0
+	}
+
+	return p.Curve.XpFor(ge.Level+1) - ge.Xp
+}
+
+// levelUp raises the base stats of ge by the rolled growth, pools gaining as
+// much as their maximum, and teaches the unlocked abilities.
+func (lv *LevelSystem) levelUp(ge *components.GameEntity, p *components.Progression) *components.LevelGain {
+	ge.Level++
+	gain := p.Grow(ge, ge.Level, lv.Rand)
+
+	if ge.Stats == nil {
+		ge.Stats = components.NewStats(nil)
+	}
+	for _, stat := range components.StatNames {
+		value, ok := gain.Stats[stat]
+		if !ok || value == 0 {
+			continue
+		}
+
+		pool := ge.Stats.IsPool(stat) && value > 0
+		if lv.st == nil {
+			ge.Stats.Base[stat] += value
+			ge.Stats.ClampPools()
+			if pool {
+				ge.Stats.AddPool(stat, value)
+			}
+			continue
+		}
+
+		lv.st.SetBase(ge, stat, ge.Stats.Base[stat]+value)
+		if pool {
+			lv.st.AddPool(ge, stat, value)
+		}
+	}
+	ge.Abilities = append(ge.Abilities, gain.Abilities...)
+
+	fmt.Printf("LV:levelUp - %s reached level %d\n", ge.Name, ge.Level)
+	lv.ev.Dispatch(EventLevelUp, map[string]any{
+		"gameEntity": ge,
+		"level":      ge.Level,
+		"gain":       gain,
+	})
+
+	return gain
+}
+
+// Summary opens a screen listing the gains, closed by its OK item.
+func (lv *LevelSystem) Summary(gains []*components.LevelGain) *components.Menu {
+	lv.CloseSummary()
+
+	lines := make([]string, 0)
+	for _, gain := range gains {
+		lines = append(lines, fmt.Sprintf("%s reached level %d", gain.GameEntity.Name, gain.Level))
+		for _, stat := range components.StatNames {
+			if value := gain.Stats[stat]; value != 0 {
+				lines = append(lines, fmt.Sprintf("  %s %+g", stat, value))
+			}
+		}
+		for _, ability := range gain.Abilities {
+			lines = append(lines, fmt.Sprintf("  Learned %s", ability))
+		}
+	}
+	lines = append(lines, LevelSummaryOk)
+
+	width := float32(LevelSummaryWidth)
+	height := float32(len(lines)+1) * LevelSummaryLineHeight
+	lv.summary = lv.ms.NewMenu("level-summary", common.SpaceComponent{
+		Position: engo.Point{
+			X: engo.WindowWidth()/2 - width/2,
+			Y: engo.WindowHeight()/2 - height/2,
+		},
+		Width:  width,
+		Height: height,
+	}, lv.ui.LoadSprite("box"), lv.ui.LoadSprite("cursor"), lines, lv.ui.GetFont(LevelSummaryFont, LevelSummaryFontSize, color.Black), false)
+	lv.summary.Container.RenderComponent.SetZIndex(components.LayerUi)
+
+	return lv.summary
+}
+
+func (lv *LevelSystem) CloseSummary() {
+	if lv.summary == nil {
+		return
+	}
+
+	lv.ms.Destroy(lv.summary)
+	lv.summary = nil
+}
+
+func (lv *LevelSystem) Debug() {
+	fmt.Printf("*** Level System DEBUG ***\n")
+	names := lv.Progressions.Names()
+	fmt.Printf("Progressions: %d\n", len(names))
+	for _, name := range names {
+		p := lv.Progressions.Get(name)
+		xp := make([]int, 0)
+		for level := 2; level <= 6 && level <= p.Curve.MaxLevel(); level++ {
+			xp = append(xp, p.Curve.XpFor(level))
+		}
+		fmt.Printf("\t- %s: %s curve up to level %d, xp %v..., growth %v, abilities %v\n", name, p.Curve.Type, p.Curve.MaxLevel(), xp, p.Growth, p.Abilities)
+	}
+	fmt.Printf("\n")
+}
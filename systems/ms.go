@@ -24,6 +24,7 @@ type MenuSystem struct {
 	ev    *EventSystem
 	ds    *DragSystem
 	ui    *UiSystem
+	sm    *FsmSystem
 	menus map[string]*components.Menu
 }
 
@@ -40,6 +41,8 @@ func (ms *MenuSystem) New(w *ecs.World) {
 			ms.ds = sys
 		case *UiSystem:
 			ms.ui = sys
+		case *FsmSystem:
+			ms.sm = sys
 		}
 	}
 
@@ -50,13 +53,12 @@ func (ms *MenuSystem) New(w *ecs.World) {
 		evt := msg.(*components.Event)
 		menu := evt.Data["menu"].(*components.Menu)
 
-		// Hide cursor and make children draggable
 		for _, m := range ms.menus {
 			if m.Container == menu.Container {
-				toggle := !m.Container.RenderComponent.Hidden
-				m.Container.RenderComponent.Hidden = toggle
-				for _, e := range m.Container.Children() {
-					ms.em.Get(e).RenderComponent.Hidden = toggle
+				if m.State.Is(components.MenuHidden) {
+					ms.Show(m)
+				} else {
+					ms.Hide(m)
 				}
 			}
 		}
@@ -66,26 +68,10 @@ func (ms *MenuSystem) New(w *ecs.World) {
 		evt := msg.(*components.Event)
 		entity := evt.Data["entity"].(*components.Entity)
 
-		// Hide cursor and make children draggable
 		for _, m := range ms.menus {
-			if m.Container == entity {
-				m.Cursor.RenderComponent.Hidden = true
-				for _, e := range m.Container.Children() {
-					ms.em.Get(e).IsDraggable = true
-				}
-			}
-		}
-	})
-
-	ms.ev.Listen(EventStopDrag, func(msg engo.Message) {
-		evt := msg.(*components.Event)
-		entity := evt.Data["entity"].(*components.Entity)
-
-		// Making sure that parent container is at ZIndex 0 and re-align items in case of speedy drag&drop
-		for _, m := range ms.menus {
-			if m.Container == entity {
-				ms.AlignItems(m)
-				m.Container.RenderComponent.StartZIndex = 0
+			if m.Container == entity && m.State.Can(components.MenuDrag) {
+				ms.ds.Claim()
+				m.State.Fire(components.MenuDrag)
 			}
 		}
 	})
@@ -97,6 +83,21 @@ func (ms *MenuSystem) Update(dt float32) {
 	}
 
 	for _, m := range ms.menus {
+		if m.State.Is(components.MenuDragging) {
+			if m.Container.MouseComponent.Released {
+				m.State.Fire(components.MenuDrop)
+				continue
+			}
+			ms.ds.Follow(m.Container)
+			for _, e := range m.Container.Children() {
+				ms.ds.Follow(ms.em.Get(e))
+			}
+			continue
+		}
+		if !m.State.Is(components.MenuOpen) {
+			continue
+		}
+
 		//if m.Container.MouseComponent.RightClicked {
 		//	ms.es.Dispatch(EventMenuToggle, map[string]interface{}{
 		//		"menu": m,
@@ -161,6 +162,7 @@ func (ms *MenuSystem) NewMenu(name string, spaceComponent common.SpaceComponent,
 		Cursor:         ms.em.NewEntity(),
 	}
 	menu.Font = font
+	menu.State = ms.newState(menu)
 	menu.Container.Refresh = true
 	menu.Container.Ref = fmt.Sprintf("menu-%s-container", menu.Name)
 	menu.Container.SpaceComponent = common.SpaceComponent{
@@ -215,7 +217,6 @@ func (ms *MenuSystem) AlignItems(menu *components.Menu) {
 		_, h, _ := menu.Font.TextDimensions(e.Drawable.(common.Text).Text)
 		e.Position.X = menu.Container.Position.X + MenuItemStartX
 		e.Position.Y = y
-		e.IsDraggable = false
 		y += float32(h)
 	}
 
@@ -251,6 +252,20 @@ func (ms *MenuSystem) SetItems(menu *components.Menu, items []string) {
 	}
 }
 
+func (ms *MenuSystem) Get(name string) *components.Menu {
+	return ms.menus[name]
+}
+
+func (ms *MenuSystem) ItemText(menu *components.Menu, index int) string {
+	for i, c := range menu.Container.Children() {
+		if i == index {
+			return ms.em.Get(c).Drawable.(common.Text).Text
+		}
+	}
+
+	return ""
+}
+
 func (ms *MenuSystem) SelectIndex(menu *components.Menu, index int) {
 	menu.Selected = index
 	for i, c := range menu.Container.Children() {
@@ -289,11 +304,45 @@ func (ms *MenuSystem) IsDisabled(menu *components.Menu, e *components.Entity) bo
 	return false
 }
 
+// newState builds the state machine of menu: entering open shows the
+// container and its items, entering hidden hides them, only open menus
+// handle clicks and dragging menus follow the mouse until released.
+func (ms *MenuSystem) newState(menu *components.Menu) *components.Fsm {
+	fsm := components.NewFsm("menu-"+menu.Name, components.MenuOpen).
+		AddState(components.MenuOpen, func(from string, to string) {
+			ms.setHidden(menu, false)
+		}, nil).
+		AddState(components.MenuHidden, func(from string, to string) {
+			ms.setHidden(menu, true)
+		}, nil).
+		AddState(components.MenuDragging, func(from string, to string) {
+			// Hide cursor and grab the items along the container
+			menu.Cursor.RenderComponent.Hidden = true
+			ms.ds.Grab(menu.Container)
+			for _, e := range menu.Container.Children() {
+				ms.ds.Grab(ms.em.Get(e))
+			}
+		}, func(from string, to string) {
+			// Making sure that parent container is at ZIndex 0 and re-align items in case of speedy drag&drop
+			ms.AlignItems(menu)
+			menu.Container.RenderComponent.StartZIndex = 0
+			ms.ds.Drop(menu.Container)
+		}).
+		AddTransition(components.MenuShow, []string{components.MenuHidden}, components.MenuOpen, nil).
+		AddTransition(components.MenuHide, []string{components.MenuOpen, components.MenuDragging}, components.MenuHidden, nil).
+		AddTransition(components.MenuDrag, []string{components.MenuOpen}, components.MenuDragging, nil).
+		AddTransition(components.MenuDrop, []string{components.MenuDragging}, components.MenuOpen, nil)
+
+	if ms.sm != nil {
+		ms.sm.Add(fsm)
+	}
+
+	return fsm
+}
+
 func (ms *MenuSystem) Show(menu *components.Menu) {
-	menu.Container.Hidden = false
-	for _, c := range menu.Container.Children() {
-		ge := ms.em.Get(c)
-		ge.Hidden = false
+	if menu.State.Can(components.MenuShow) {
+		menu.State.Fire(components.MenuShow)
 	}
 }
 
@@ -302,10 +351,16 @@ func (ms *MenuSystem) Hide(menu *components.Menu) {
 		return
 	}
 
-	menu.Container.Hidden = true
+	if menu.State.Can(components.MenuHide) {
+		menu.State.Fire(components.MenuHide)
+	}
+}
+
+func (ms *MenuSystem) setHidden(menu *components.Menu, hidden bool) {
+	menu.Container.Hidden = hidden
 	for _, c := range menu.Container.Children() {
 		ge := ms.em.Get(c)
-		ge.Hidden = true
+		ge.Hidden = hidden
 	}
 }
 
@@ -357,6 +412,9 @@ func (ms *MenuSystem) Destroy(menu *components.Menu) {
 		menu.Cursor = nil
 	}
 
+	if ms.sm != nil {
+		ms.sm.RemoveFsm(menu.State)
+	}
 	delete(ms.menus, menu.Name)
 }
 
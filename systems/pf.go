@@ -0,0 +1,100 @@
+package systems
+
+import (
+	"fmt"
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/engo/common"
+	"tools/components"
+)
+
+type PrefabSystem struct {
+	em      *EntityManager
+	ui      *UiSystem
+	Prefabs *components.Prefabs
+}
+
+func (pf *PrefabSystem) New(w *ecs.World) {
+	pf.Prefabs = components.NewPrefabs()
+
+	for _, system := range w.Systems() {
+		switch sys := system.(type) {
+		case *EntityManager:
+			pf.em = sys
+		case *UiSystem:
+			pf.ui = sys
+		}
+	}
+}
+
+func (pf *PrefabSystem) Update(dt float32) {
+	if engo.Input.Button("F4").JustPressed() {
+		pf.Debug()
+	}
+}
+
+func (pf *PrefabSystem) Remove(e ecs.BasicEntity) {
+}
+
+func (pf *PrefabSystem) Load(dir string) error {
+	return pf.Prefabs.LoadDir(dir)
+}
+
+// Instantiate creates a GameEntity of the prefab at position, its sprite
+// Entity is registered in the EntityManager. The prefab size defaults to the
+// sprite size.
+func (pf *PrefabSystem) Instantiate(name string, position engo.Point) (*components.GameEntity, error) {
+	ge, err := pf.Prefabs.NewGameEntity(name)
+	if err != nil {
+		return nil, err
+	}
+	if ge.Sprite == "" {
+		return ge, nil
+	}
+
+	prefab, _ := pf.Prefabs.Resolve(name)
+	sprite := pf.ui.LoadSprite(ge.Sprite)
+	width, height := prefab.Width, prefab.Height
+	if width == 0 {
+		width = sprite.Width()
+	}
+	if height == 0 {
+		height = sprite.Height()
+	}
+
+	e := pf.em.NewEntity()
+	e.Ref = fmt.Sprintf("prefab-%s-%d", name, e.ID())
+	e.SpaceComponent = common.SpaceComponent{
+		Position: position,
+		Width:    width,
+		Height:   height,
+	}
+	e.RenderComponent = common.RenderComponent{
+		Drawable: sprite,
+		Scale: engo.Point{
+			X: width / sprite.Width(),
+			Y: height / sprite.Height(),
+		},
+	}
+	e.SetZIndex(components.LayerWorld)
+	pf.em.Add(e)
+
+	ge.Entity = e
+
+	return ge, nil
+}
+
+func (pf *PrefabSystem) Debug() {
+	fmt.Printf("*** Prefab System DEBUG ***\n")
+	names := pf.Prefabs.Names()
+	fmt.Printf("Instances: %d\n", len(names))
+	for _, name := range names {
+		prefab, err := pf.Prefabs.Resolve(name)
+		if err != nil {
+			fmt.Printf("\t- %s: %s\n", name, err)
+			continue
+		}
+		fmt.Printf("\t- %s extends %q: %s sprite %s, stats %v, abilities %v\n", name, pf.Prefabs.Get(name).Extends, prefab.Type, prefab.Sprite, prefab.Stats, prefab.Abilities)
+	}
+	fmt.Printf("\n")
+}
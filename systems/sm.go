@@ -0,0 +1,80 @@
+package systems
+
+import (
+	"fmt"
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+	"tools/components"
+)
+
+const EventStateChanged = "EventStateChanged"
+
+// FsmSystem dispatches the state changes of the machines it holds.
+type FsmSystem struct {
+	ev       *EventSystem
+	machines []*components.Fsm
+}
+
+func (sm *FsmSystem) New(w *ecs.World) {
+	for _, system := range w.Systems() {
+		switch sys := system.(type) {
+		case *EventSystem:
+			sm.ev = sys
+		}
+	}
+
+	sm.ev.NewEvent(EventStateChanged)
+}
+
+func (sm *FsmSystem) Update(dt float32) {
+	if engo.Input.Button("4").JustPressed() {
+		sm.Debug()
+	}
+}
+
+func (sm *FsmSystem) Remove(e ecs.BasicEntity) {
+}
+
+// Add makes every change of fsm dispatch EventStateChanged.
+func (sm *FsmSystem) Add(fsm *components.Fsm) *components.Fsm {
+	fsm.OnChange = func(fsm *components.Fsm, transition string, from string, to string) {
+		sm.ev.Dispatch(EventStateChanged, map[string]any{
+			"fsm":        fsm,
+			"transition": transition,
+			"from":       from,
+			"to":         to,
+		})
+	}
+	sm.machines = append(sm.machines, fsm)
+
+	return fsm
+}
+
+func (sm *FsmSystem) RemoveFsm(fsm *components.Fsm) {
+	for i, m := range sm.machines {
+		if m == fsm {
+			fsm.OnChange = nil
+			sm.machines = append(sm.machines[:i], sm.machines[i+1:]...)
+			return
+		}
+	}
+}
+
+func (sm *FsmSystem) Get(name string) *components.Fsm {
+	for _, m := range sm.machines {
+		if m.Name == name {
+			return m
+		}
+	}
+
+	return nil
+}
+
+func (sm *FsmSystem) Debug() {
+	fmt.Printf("*** FSM System DEBUG ***\n")
+	fmt.Printf("Instances: %d\n", len(sm.machines))
+	for _, m := range sm.machines {
+		fmt.Printf("\t- %s: %s, history %v\n", m.Name, m.Current, m.History)
+	}
+	fmt.Printf("\n")
+}
@@ -0,0 +1,273 @@
+package systems
+
+import (
+	"fmt"
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+	"image/color"
+	"math"
+	"tools/components"
+)
+
+const (
+	EventStatusApplied   = "EventStatusApplied"
+	EventStatusExpired   = "EventStatusExpired"
+	EventStatusTriggered = "EventStatusTriggered"
+)
+
+// StatusSystem runs the statuses of entities. It also owns their color: their
+// highlight, else the tint of their most visible status, else their own.
+type StatusSystem struct {
+	ev         *EventSystem
+	st         *StatsSystem
+	el         *ElementSystem
+	entities   []*components.GameEntity
+	colors     map[*components.GameEntity]color.Color
+	highlights map[*components.GameEntity]color.Color
+}
+
+func (ss *StatusSystem) New(w *ecs.World) {
+	ss.entities = make([]*components.GameEntity, 0)
+	ss.colors = make(map[*components.GameEntity]color.Color)
+	ss.highlights = make(map[*components.GameEntity]color.Color)
+
+	for _, system := range w.Systems() {
+		switch sys := system.(type) {
+		case *EventSystem:
+			ss.ev = sys
+		case *StatsSystem:
+			ss.st = sys
+		}
+	}
+
+	ss.ev.NewEvent(EventStatusApplied)
+	ss.ev.NewEvent(EventStatusExpired)
+	ss.ev.NewEvent(EventStatusTriggered)
+
+	ss.ev.Listen(EventGameEntityKilled, func(msg engo.Message) {
+		evt := msg.(*components.Event)
+		ge := evt.Data["gameEntity"].(*components.GameEntity)
+
+		if ss.has(ge) && !ge.HasStatus(components.StatusDead) {
+			ss.CureAll(ge)
+			ss.Apply(ge, components.NewStatusEffect(components.StatusDead, 0))
+		}
+	})
+
+	// Damage of actions wakes sleeping entities up, status ticks such as
+	// poison do not
+	ss.ev.Listen(EventPoolChanged, func(msg engo.Message) {
+		evt := msg.(*components.Event)
+		ge := evt.Data["gameEntity"].(*components.GameEntity)
+		stat := evt.Data["stat"].(string)
+		if stat != components.StatHp || evt.Data["source"] != PoolSourceAction {
+			return
+		}
+		if evt.Data["new"].(float64) >= evt.Data["old"].(float64) || !ss.has(ge) {
+			return
+		}
+
+		for _, s := range append([]*components.StatusEffect(nil), ge.Statuses...) {
+			if s.BreakOnDamage {
+				ss.Cure(ge, s.Name)
+			}
+		}
+	})
+}
+
+func (ss *StatusSystem) Update(dt float32) {
+	if engo.Input.Button("F9").JustPressed() {
+		ss.Debug()
+	}
+
+	for _, ge := range ss.entities {
+		ss.expire(ge, ss.advance(ge, float64(dt), true))
+	}
+}
+
+func (ss *StatusSystem) Add(ge *components.GameEntity) {
+	ss.entities = append(ss.entities, ge)
+}
+
+func (ss *StatusSystem) Remove(e ecs.BasicEntity) {
+	for i, ge := range ss.entities {
+		if ge.Entity != nil && ge.Entity.ID() == e.ID() {
+			ss.entities = append(ss.entities[:i], ss.entities[i+1:]...)
+			delete(ss.colors, ge)
+			delete(ss.highlights, ge)
+			return
+		}
+	}
+}
+
+// Apply adds a status to ge, an existing status with the same name is
+// refreshed to the longest duration instead.
+func (ss *StatusSystem) Apply(ge *components.GameEntity, status *components.StatusEffect) {
+	status.Remaining = status.Duration
+
+	if current := ge.GetStatus(status.Name); current != nil {
+		if status.Duration == 0 || current.Duration > 0 && status.Duration > current.Remaining {
+			current.Duration = status.Duration
+			current.Remaining = status.Duration
+		}
+		return
+	}
+
+	ge.Statuses = append(ge.Statuses, status)
+	ss.paint(ge)
+
+	ss.ev.Dispatch(EventStatusApplied, map[string]any{
+		"gameEntity": ge,
+		"status":     status,
+	})
+}
+
+func (ss *StatusSystem) Cure(ge *components.GameEntity, name string) {
+	for i, s := range ge.Statuses {
+		if s.Name == name {
+			ge.Statuses = append(ge.Statuses[:i], ge.Statuses[i+1:]...)
+			ss.paint(ge)
+
+			ss.ev.Dispatch(EventStatusExpired, map[string]any{
+				"gameEntity": ge,
+				"status":     s,
+			})
+			return
+		}
+	}
+}
+
+func (ss *StatusSystem) CureAll(ge *components.GameEntity) {
+	for len(ge.Statuses) > 0 {
+		ss.Cure(ge, ge.Statuses[0].Name)
+	}
+}
+
+// Turn runs the per-turn effects of ge at the start of its turn and returns
+// whether it can act. Statuses expire after this check, so a two turns sleep
+// skips two turns.
+func (ss *StatusSystem) Turn(ge *components.GameEntity) bool {
+	expired := ss.advance(ge, 1, false)
+	canAct := ge.CanAct()
+	ss.expire(ge, expired)
+
+	return canAct
+}
+
+func (ss *StatusSystem) advance(ge *components.GameEntity, elapsed float64, realtime bool) []*components.StatusEffect {
+	expired := make([]*components.StatusEffect, 0)
+	for _, s := range append([]*components.StatusEffect(nil), ge.Statuses...) {
+		if s.Realtime != realtime || ge.GetStatus(s.Name) != s {
+			continue
+		}
+
+		for i := s.Advance(elapsed); i > 0; i-- {
+			ss.trigger(ge, s)
+		}
+
+		if s.Expired() {
+			expired = append(expired, s)
+		}
+	}
+
+	return expired
+}
+
+func (ss *StatusSystem) expire(ge *components.GameEntity, expired []*components.StatusEffect) {
+	for _, s := range expired {
+		if ge.GetStatus(s.Name) == s {
+			ss.Cure(ge, s.Name)
+		}
+	}
+}
+
+func (ss *StatusSystem) trigger(ge *components.GameEntity, s *components.StatusEffect) {
+	damage := s.Damage
+	if ge.Stats != nil {
+		damage += ge.Stats.Get(components.StatHp) * s.DamagePercent / 100
+	}
+
+	damage = math.Round(damage)
+
+	// Healing ticks ignore resistances
+	affinity := ""
+	if ss.el != nil && s.Element != "" && damage > 0 {
+		damage, affinity = ss.el.Scale(ge, s.Element, damage)
+	}
+
+	ss.ev.Dispatch(EventStatusTriggered, map[string]any{
+		"gameEntity": ge,
+		"status":     s,
+		"damage":     damage,
+		"element":    s.Element,
+		"affinity":   affinity,
+	})
+
+	if damage != 0 && ss.st != nil && ge.Stats != nil {
+		ss.st.AddPoolFrom(ge, components.StatHp, -damage, PoolSourceStatus)
+	}
+}
+
+// SetElements scales the damage of statuses with an element through el.
+func (ss *StatusSystem) SetElements(el *ElementSystem) {
+	ss.el = el
+}
+
+// Highlight colors ge with c over its statuses until Unhighlight.
+func (ss *StatusSystem) Highlight(ge *components.GameEntity, c color.Color) {
+	ss.highlights[ge] = c
+	ss.paint(ge)
+}
+
+func (ss *StatusSystem) Unhighlight(ge *components.GameEntity) {
+	delete(ss.highlights, ge)
+	ss.paint(ge)
+}
+
+// paint colors the entity with its highlight, or its most visible status, and
+// restores its own color once neither is left.
+func (ss *StatusSystem) paint(ge *components.GameEntity) {
+	if ge.Entity == nil {
+		return
+	}
+
+	c := ss.highlights[ge]
+	if c == nil {
+		c = ge.StatusColor()
+	}
+	original, tinted := ss.colors[ge]
+	if c == nil {
+		if tinted {
+			ge.Entity.RenderComponent.Color = original
+			delete(ss.colors, ge)
+		}
+		return
+	}
+
+	if !tinted {
+		ss.colors[ge] = ge.Entity.RenderComponent.Color
+	}
+	ge.Entity.RenderComponent.Color = c
+}
+
+func (ss *StatusSystem) has(ge *components.GameEntity) bool {
+	for _, e := range ss.entities {
+		if e == ge {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (ss *StatusSystem) Debug() {
+	fmt.Printf("*** Status System DEBUG ***\n")
+	fmt.Printf("Instances: %d\n", len(ss.entities))
+	for _, ge := range ss.entities {
+		fmt.Printf("\t- %s\n", ge.Name)
+		for _, s := range ge.Statuses {
+			fmt.Printf("\t\t- %s: %g/%g left, skip turn: %t\n", s.Name, s.Remaining, s.Duration, s.SkipTurn)
+		}
+	}
+	fmt.Printf("\n")
+}
@@ -0,0 +1,226 @@
+package systems
+
+import (
+	"fmt"
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+	"tools/components"
+)
+
+const (
+	EventStatChanged      = "EventStatChanged"
+	EventPoolChanged      = "EventPoolChanged"
+	EventModifierExpired  = "EventModifierExpired"
+	EventGameEntityKilled = "EventGameEntityKilled"
+
+	// Sources of pool changes, sent as "source" with EventPoolChanged
+	PoolSourceAction = "action"
+	PoolSourceStatus = "status"
+)
+
+type StatsSystem struct {
+	ev       *EventSystem
+	entities []*components.GameEntity
+}
+
+func (st *StatsSystem) New(w *ecs.World) {
+	st.entities = make([]*components.GameEntity, 0)
+
+	for _, system := range w.Systems() {
+		switch sys := system.(type) {
+		case *EventSystem:
+			st.ev = sys
+		}
+	}
+
+	st.ev.NewEvent(EventStatChanged)
+	st.ev.NewEvent(EventPoolChanged)
+	st.ev.NewEvent(EventModifierExpired)
+	st.ev.NewEvent(EventGameEntityKilled)
+}
+
+func (st *StatsSystem) Update(dt float32) {
+	if engo.Input.Button("F8").JustPressed() {
+		st.Debug()
+	}
+
+	for _, ge := range st.entities {
+		for _, m := range ge.Stats.Modifiers {
+			if m.Realtime && m.Duration > 0 {
+				st.tick(ge, float64(dt), true)
+				break
+			}
+		}
+	}
+}
+
+func (st *StatsSystem) Add(ge *components.GameEntity) {
+	if ge.Stats == nil {
+		ge.Stats = components.NewStats(nil)
+	}
+
+	st.entities = append(st.entities, ge)
+}
+
+func (st *StatsSystem) Remove(e ecs.BasicEntity) {
+	for i, ge := range st.entities {
+		if ge.Entity != nil && ge.Entity.ID() == e.ID() {
+			st.entities = append(st.entities[:i], st.entities[i+1:]...)
+			return
+		}
+	}
+}
+
+func (st *StatsSystem) RemoveGameEntity(ge *components.GameEntity) {
+	for i, e := range st.entities {
+		if e == ge {
+			st.entities = append(st.entities[:i], st.entities[i+1:]...)
+			return
+		}
+	}
+}
+
+func (st *StatsSystem) GetEntities() []*components.GameEntity {
+	return st.entities
+}
+
+func (st *StatsSystem) SetBase(ge *components.GameEntity, stat string, value float64) {
+	st.change(ge, func() {
+		ge.Stats.Base[stat] = value
+		ge.Stats.ClampPools()
+	})
+}
+
+func (st *StatsSystem) AddModifier(ge *components.GameEntity, m *components.Modifier) bool {
+	applied := false
+	st.change(ge, func() {
+		applied = ge.Stats.AddModifier(m)
+	})
+
+	return applied
+}
+
+func (st *StatsSystem) RemoveModifier(ge *components.GameEntity, m *components.Modifier) {
+	st.change(ge, func() {
+		ge.Stats.RemoveModifier(m)
+	})
+}
+
+func (st *StatsSystem) RemoveSource(ge *components.GameEntity, source string) []*components.Modifier {
+	removed := make([]*components.Modifier, 0)
+	st.change(ge, func() {
+		removed = ge.Stats.RemoveSource(source)
+	})
+
+	return removed
+}
+
+// Tick advances turn based modifiers of ge by a number of turns.
+func (st *StatsSystem) Tick(ge *components.GameEntity, turns float64) {
+	st.tick(ge, turns, false)
+}
+
+// AddPool changes a pool stat such as HP, dispatching EventGameEntityKilled when
+// HP drops to 0, and returns the change actually applied.
+func (st *StatsSystem) AddPool(ge *components.GameEntity, stat string, delta float64) float64 {
+	return st.AddPoolFrom(ge, stat, delta, "")
+}
+
+// AddPoolFrom is AddPool telling listeners where the change comes from, such
+// as PoolSourceAction.
+func (st *StatsSystem) AddPoolFrom(ge *components.GameEntity, stat string, delta float64, source string) float64 {
+	old := ge.Stats.Pool(stat)
+	applied := ge.Stats.AddPool(stat, delta)
+	if applied == 0 {
+		return 0
+	}
+
+	st.ev.Dispatch(EventPoolChanged, map[string]any{
+		"gameEntity": ge,
+		"stat":       stat,
+		"old":        old,
+		"new":        ge.Stats.Pool(stat),
+		"source":     source,
+	})
+
+	if stat == components.StatHp && ge.Stats.Pool(stat) <= 0 {
+		st.ev.Dispatch(EventGameEntityKilled, map[string]any{
+			"gameEntity": ge,
+		})
+	}
+
+	return applied
+}
+
+func (st *StatsSystem) tick(ge *components.GameEntity, elapsed float64, realtime bool) {
+	var expired []*components.Modifier
+	st.change(ge, func() {
+		expired = ge.Stats.Tick(elapsed, realtime)
+	})
+
+	for _, m := range expired {
+		st.ev.Dispatch(EventModifierExpired, map[string]any{
+			"gameEntity": ge,
+			"modifier":   m,
+		})
+	}
+}
+
+// change runs f and dispatches EventStatChanged for every stat whose final
+// value moved, and EventPoolChanged for pools clamped by a lower maximum.
+func (st *StatsSystem) change(ge *components.GameEntity, f func()) {
+	before := ge.Stats.Values()
+	pools := make(map[string]float64)
+	for stat, v := range ge.Stats.Pools {
+		pools[stat] = v
+	}
+
+	f()
+
+	after := ge.Stats.Values()
+	for stat := range before {
+		if _, ok := after[stat]; !ok {
+			after[stat] = ge.Stats.Get(stat)
+		}
+	}
+	for stat, value := range after {
+		if before[stat] != value {
+			st.ev.Dispatch(EventStatChanged, map[string]any{
+				"gameEntity": ge,
+				"stat":       stat,
+				"old":        before[stat],
+				"new":        value,
+			})
+		}
+	}
+	for stat, old := range pools {
+		if ge.Stats.Pools[stat] != old {
+			st.ev.Dispatch(EventPoolChanged, map[string]any{
+				"gameEntity": ge,
+				"stat":       stat,
+				"old":        old,
+				"new":        ge.Stats.Pools[stat],
+				"source":     "",
+			})
+		}
+	}
+}
+
+func (st *StatsSystem) Debug() {
+	fmt.Printf("*** Stats System DEBUG ***\n")
+	fmt.Printf("Instances: %d\n", len(st.entities))
+	for _, ge := range st.entities {
+		fmt.Printf("\t- %s\n", ge.Name)
+		for _, stat := range ge.Stats.Names() {
+			if ge.Stats.IsPool(stat) {
+				fmt.Printf("\t\t- %s: %g/%g (base %g)\n", stat, ge.Stats.Pool(stat), ge.Stats.Get(stat), ge.Stats.Base[stat])
+				continue
+			}
+			fmt.Printf("\t\t- %s: %g (base %g)\n", stat, ge.Stats.Get(stat), ge.Stats.Base[stat])
+		}
+		for _, m := range ge.Stats.Modifiers {
+			fmt.Printf("\t\t\t- %s %s %g from %s, %g left\n", m.Stat, m.ID, m.Value, m.Source, m.Remaining)
+		}
+	}
+	fmt.Printf("\n")
+}
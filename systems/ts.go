@@ -0,0 +1,291 @@
+package systems
+
+import (
+	"fmt"
+	"github.com/EngoEngine/ecs"
+	"github.com/EngoEngine/engo"
+	"github.com/EngoEngine/engo/common"
+	"image/color"
+	"math/rand"
+	"tools/components"
+)
+
+const (
+	EventTargetChanged   = "EventTargetChanged"
+	EventTargetConfirmed = "EventTargetConfirmed"
+	EventTargetCanceled  = "EventTargetCanceled"
+
+	TargetCursorSize = 30
+)
+
+type TargetSystem struct {
+	ev          *EventSystem
+	em          *EntityManager
+	ui          *UiSystem
+	ss          *StatusSystem
+	cursor      *components.Entity
+	selection   *components.Selection
+	highlighted map[*components.GameEntity]color.Color
+	Rand        *rand.Rand
+}
+
+func (ts *TargetSystem) New(w *ecs.World) {
+	ts.highlighted = make(map[*components.GameEntity]color.Color)
+	ts.Rand = components.GameStreams.Get(components.StreamCombat).Rand
+
+	for _, system := range w.Systems() {
+		switch sys := system.(type) {
+		case *EventSystem:
+			ts.ev = sys
+		case *EntityManager:
+			ts.em = sys
+		case *UiSystem:
+			ts.ui = sys
+		case *StatusSystem:
+			ts.ss = sys
+		}
+	}
+
+	ts.ev.NewEvent(EventTargetChanged)
+	ts.ev.NewEvent(EventTargetConfirmed)
+	ts.ev.NewEvent(EventTargetCanceled)
+}
+
+func (ts *TargetSystem) Update(dt float32) {
+	if engo.Input.Button("F11").JustPressed() {
+		ts.Debug()
+	}
+
+	if ts.selection == nil {
+		return
+	}
+
+	switch {
+	case engo.Input.Button("RIGHT").JustPressed(), engo.Input.Button("TAB").JustPressed():
+		ts.Next()
+		return
+	case engo.Input.Button("LEFT").JustPressed():
+		ts.Prev()
+		return
+	case engo.Input.Button("ENTER").JustPressed():
+		ts.Confirm()
+		return
+	case engo.Input.Button("ESCAPE").JustPressed():
+		ts.Cancel()
+		return
+	}
+
+	if engo.Input.Mouse.Action == engo.Press && engo.Input.Mouse.Button == engo.MouseButtonRight {
+		ts.Cancel()
+		return
+	}
+
+	for i, ge := range ts.selection.Candidates {
+		if ge.Entity == nil {
+			continue
+		}
+
+		if ge.Entity.MouseComponent.Clicked {
+			ts.focus(i)
+			ts.Confirm()
+			return
+		}
+
+		if ge.Entity.MouseComponent.Enter && i != ts.selection.Index {
+			ts.focus(i)
+		}
+	}
+}
+
+func (ts *TargetSystem) Remove(e ecs.BasicEntity) {
+}
+
+// Select starts an interactive choice of targets for actor, it ends with
+// EventTargetConfirmed or EventTargetCanceled.
+func (ts *TargetSystem) Select(battle *components.Battle, actor *components.GameEntity, targeting components.Targeting) *components.Selection {
+	if ts.selection != nil {
+		ts.Cancel()
+	}
+
+	ts.selection = components.NewSelection(battle, actor, targeting)
+	ts.refresh()
+
+	return ts.selection
+}
+
+func (ts *TargetSystem) GetSelection() *components.Selection {
+	return ts.selection
+}
+
+func (ts *TargetSystem) Next() {
+	if ts.selection == nil {
+		return
+	}
+
+	ts.selection.Next()
+	ts.changed()
+}
+
+func (ts *TargetSystem) Prev() {
+	if ts.selection == nil {
+		return
+	}
+
+	ts.selection.Prev()
+	ts.changed()
+}
+
+func (ts *TargetSystem) Confirm() {
+	selection := ts.selection
+	if selection == nil {
+		return
+	}
+
+	targets := selection.Battle.ResolveTargets(selection.Actor, selection.Targeting, selection.Focus(), ts.Rand)
+	if len(targets) == 0 {
+		fmt.Printf("TS:Confirm - No target for %s\n", selection.Targeting)
+		return
+	}
+
+	ts.end()
+	ts.ev.Dispatch(EventTargetConfirmed, map[string]any{
+		"selection": selection,
+		"actor":     selection.Actor,
+		"targets":   targets,
+	})
+}
+
+func (ts *TargetSystem) Cancel() {
+	selection := ts.selection
+	if selection == nil {
+		return
+	}
+
+	ts.end()
+	ts.ev.Dispatch(EventTargetCanceled, map[string]any{
+		"selection": selection,
+		"actor":     selection.Actor,
+	})
+}
+
+func (ts *TargetSystem) focus(index int) {
+	if index == ts.selection.Index {
+		return
+	}
+
+	ts.selection.Index = index
+	ts.changed()
+}
+
+func (ts *TargetSystem) changed() {
+	ts.refresh()
+
+	ts.ev.Dispatch(EventTargetChanged, map[string]any{
+		"selection": ts.selection,
+		"actor":     ts.selection.Actor,
+		"targets":   ts.selection.Affected(),
+	})
+}
+
+func (ts *TargetSystem) end() {
+	ts.selection = nil
+	ts.unhighlight()
+	if ts.cursor != nil {
+		ts.cursor.Hidden = true
+	}
+}
+
+// refresh highlights the affected entities and moves the cursor next to the
+// focused one.
+func (ts *TargetSystem) refresh() {
+	ts.unhighlight()
+	for _, ge := range ts.selection.Affected() {
+		if ge.Entity == nil {
+			continue
+		}
+		if ts.ss != nil {
+			ts.highlighted[ge] = nil
+			ts.ss.Highlight(ge, components.ColorHighlight)
+			continue
+		}
+		ts.highlighted[ge] = ge.Entity.RenderComponent.Color
+		ge.Entity.RenderComponent.Color = components.ColorHighlight
+	}
+
+	focus := ts.selection.Focus()
+	if focus == nil || focus.Entity == nil {
+		if ts.cursor != nil {
+			ts.cursor.Hidden = true
+		}
+		return
+	}
+
+	cursor := ts.getCursor()
+	cursor.Hidden = false
+	cursor.Position = focus.Entity.Position
+	cursor.Position.X -= cursor.Width
+	cursor.Position.Y += (focus.Entity.Height - cursor.Height) / 2
+}
+
+// unhighlight gives the highlighted entities their color back, the status
+// system paints it over statuses changed meanwhile when there is one.
+func (ts *TargetSystem) unhighlight() {
+	for ge, c := range ts.highlighted {
+		if ts.ss != nil {
+			ts.ss.Unhighlight(ge)
+		} else if ge.Entity != nil {
+			ge.Entity.RenderComponent.Color = c
+		}
+		delete(ts.highlighted, ge)
+	}
+}
+
+// getCursor lazily creates the cursor with the menu cursor sprite.
+func (ts *TargetSystem) getCursor() *components.Entity {
+	if ts.cursor != nil {
+		return ts.cursor
+	}
+
+	sprite := ts.ui.LoadSprite("cursor")
+	ts.cursor = ts.em.NewEntity()
+	ts.cursor.Ref = "target-cursor"
+	ts.cursor.SpaceComponent = common.SpaceComponent{
+		Width:  TargetCursorSize,
+		Height: TargetCursorSize,
+	}
+	ts.cursor.RenderComponent = common.RenderComponent{
+		Drawable: sprite,
+		Scale: engo.Point{
+			X: TargetCursorSize / sprite.Width(),
+			Y: TargetCursorSize / sprite.Height(),
+		},
+	}
+	ts.cursor.SetZIndex(components.LayerUi)
+	ts.em.Add(ts.cursor)
+
+	return ts.cursor
+}
+
+func (ts *TargetSystem) Debug() {
+	fmt.Printf("*** Target System DEBUG ***\n")
+	if ts.selection == nil {
+		fmt.Printf("No selection\n\n")
+		return
+	}
+
+	fmt.Printf("Actor: %s, Targeting: %s\n", ts.selection.Actor.Name, ts.selection.Targeting)
+	affected := ts.selection.Affected()
+	for i, ge := range ts.selection.Candidates {
+		marker := ""
+		if i == ts.selection.Index {
+			marker = " <-"
+		}
+		for _, a := range affected {
+			if a == ge {
+				marker += " *"
+			}
+		}
+		fmt.Printf("\t- %s%s\n", ge.Name, marker)
+	}
+	fmt.Printf("\n")
+}
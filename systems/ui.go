@@ -128,3 +128,58 @@ func (ui *UiSystem) UpdateText(e *components.Entity, text string, color color.Co
 		Text: text,
 	}
 }
+
+// NewBar creates a gauge of the size of sc, its fill color is updated with
+// UpdateBar.
+func (ui *UiSystem) NewBar(sc common.SpaceComponent, back color.Color, fill color.Color) *components.Bar {
+	bar := &components.Bar{
+		Back: ui.em.NewEntity(),
+		Fill: ui.em.NewEntity(),
+	}
+
+	bar.Back.Ref = fmt.Sprintf("bar-%d", bar.Back.ID())
+	bar.Back.SpaceComponent = sc
+	bar.Back.RenderComponent.Drawable = common.Rectangle{}
+	bar.Back.RenderComponent.Color = back
+	bar.Back.RenderComponent.SetShader(common.LegacyHUDShader)
+	bar.Back.SetZIndex(components.LayerUiBackground)
+
+	bar.Fill.Ref = fmt.Sprintf("bar-%d-fill", bar.Back.ID())
+	bar.Fill.SpaceComponent = sc
+	bar.Fill.RenderComponent.Drawable = common.Rectangle{}
+	bar.Fill.RenderComponent.Color = fill
+	bar.Fill.RenderComponent.Hidden = true
+	bar.Fill.RenderComponent.SetShader(common.LegacyHUDShader)
+	bar.Fill.SetZIndex(components.LayerUi)
+
+	bar.Back.AppendChild(&bar.Fill.BasicEntity)
+	ui.em.Add(bar.Back)
+
+	return bar
+}
+
+// UpdateBar fills ratio (0 to 1) of the bar, a nil color keeps the current one.
+func (ui *UiSystem) UpdateBar(bar *components.Bar, ratio float32, color color.Color) {
+	if ratio < 0 {
+		ratio = 0
+	} else if ratio > 1 {
+		ratio = 1
+	}
+
+	// The render system turns a zero scale into 1, an empty bar is hidden instead
+	bar.Ratio = ratio
+	bar.Fill.RenderComponent.Scale.X = ratio
+	bar.Fill.RenderComponent.Hidden = ratio == 0
+	if color != nil {
+		bar.Fill.RenderComponent.Color = color
+	}
+}
+
+func (ui *UiSystem) MoveBar(bar *components.Bar, position engo.Point) {
+	bar.Back.Position = position
+	bar.Fill.Position = position
+}
+
+func (ui *UiSystem) RemoveBar(bar *components.Bar) {
+	ui.em.Remove(bar.Back.BasicEntity)
+}